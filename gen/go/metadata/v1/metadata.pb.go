@@ -11,6 +11,7 @@ import (
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
 	reflect "reflect"
+	sync "sync"
 	unsafe "unsafe"
 )
 
@@ -21,6 +22,121 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// OperationType is the GraphQL operation a method is exposed as
+type OperationType int32
+
+const (
+	// The operation type is derived from the method's idempotency level,
+	// streaming mode and name
+	OperationType_OPERATION_TYPE_UNSPECIFIED OperationType = 0
+	// The method is exposed as a field of Query
+	OperationType_OPERATION_TYPE_QUERY OperationType = 1
+	// The method is exposed as a field of Mutation
+	OperationType_OPERATION_TYPE_MUTATION OperationType = 2
+	// The method is exposed as a field of Subscription
+	OperationType_OPERATION_TYPE_SUBSCRIPTION OperationType = 3
+)
+
+// Enum value maps for OperationType.
+var (
+	OperationType_name = map[int32]string{
+		0: "OPERATION_TYPE_UNSPECIFIED",
+		1: "OPERATION_TYPE_QUERY",
+		2: "OPERATION_TYPE_MUTATION",
+		3: "OPERATION_TYPE_SUBSCRIPTION",
+	}
+	OperationType_value = map[string]int32{
+		"OPERATION_TYPE_UNSPECIFIED":  0,
+		"OPERATION_TYPE_QUERY":        1,
+		"OPERATION_TYPE_MUTATION":     2,
+		"OPERATION_TYPE_SUBSCRIPTION": 3,
+	}
+)
+
+func (x OperationType) Enum() *OperationType {
+	p := new(OperationType)
+	*p = x
+	return p
+}
+
+func (x OperationType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OperationType) Descriptor() protoreflect.EnumDescriptor {
+	return file_metadata_v1_metadata_proto_enumTypes[0].Descriptor()
+}
+
+func (OperationType) Type() protoreflect.EnumType {
+	return &file_metadata_v1_metadata_proto_enumTypes[0]
+}
+
+func (x OperationType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OperationType.Descriptor instead.
+func (OperationType) EnumDescriptor() ([]byte, []int) {
+	return file_metadata_v1_metadata_proto_rawDescGZIP(), []int{0}
+}
+
+// EntityKey is a key an entity can be referenced by
+type EntityKey struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The proto names of the key fields, separated by spaces, with nested
+	// selections in braces (e.g. "tenant_id sku" or "owner { user_id }")
+	Fields string `protobuf:"bytes,1,opt,name=fields,proto3" json:"fields,omitempty"`
+	// Whether the subgraph can resolve the entity by this key
+	// If not provided, the key is resolvable
+	Resolvable    *bool `protobuf:"varint,2,opt,name=resolvable,proto3,oneof" json:"resolvable,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EntityKey) Reset() {
+	*x = EntityKey{}
+	mi := &file_metadata_v1_metadata_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EntityKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EntityKey) ProtoMessage() {}
+
+func (x *EntityKey) ProtoReflect() protoreflect.Message {
+	mi := &file_metadata_v1_metadata_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EntityKey.ProtoReflect.Descriptor instead.
+func (*EntityKey) Descriptor() ([]byte, []int) {
+	return file_metadata_v1_metadata_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *EntityKey) GetFields() string {
+	if x != nil {
+		return x.Fields
+	}
+	return ""
+}
+
+func (x *EntityKey) GetResolvable() bool {
+	if x != nil && x.Resolvable != nil {
+		return *x.Resolvable
+	}
+	return false
+}
+
 var file_metadata_v1_metadata_proto_extTypes = []protoimpl.ExtensionInfo{
 	{
 		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
@@ -54,6 +170,70 @@ var file_metadata_v1_metadata_proto_extTypes = []protoimpl.ExtensionInfo{
 		Tag:           "bytes,50004,opt,name=computed_from",
 		Filename:      "metadata/v1/metadata.proto",
 	},
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*string)(nil),
+		Field:         50005,
+		Name:          "metadata.v1.field_name",
+		Tag:           "bytes,50005,opt,name=field_name",
+		Filename:      "metadata/v1/metadata.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*bool)(nil),
+		Field:         50006,
+		Name:          "metadata.v1.payload",
+		Tag:           "varint,50006,opt,name=payload",
+		Filename:      "metadata/v1/metadata.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*string)(nil),
+		Field:         50007,
+		Name:          "metadata.v1.provides_fields",
+		Tag:           "bytes,50007,opt,name=provides_fields",
+		Filename:      "metadata/v1/metadata.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*bool)(nil),
+		Field:         50008,
+		Name:          "metadata.v1.shareable",
+		Tag:           "varint,50008,opt,name=shareable",
+		Filename:      "metadata/v1/metadata.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*bool)(nil),
+		Field:         50009,
+		Name:          "metadata.v1.inaccessible",
+		Tag:           "varint,50009,opt,name=inaccessible",
+		Filename:      "metadata/v1/metadata.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*string)(nil),
+		Field:         50010,
+		Name:          "metadata.v1.override_from",
+		Tag:           "bytes,50010,opt,name=override_from",
+		Filename:      "metadata/v1/metadata.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: ([]string)(nil),
+		Field:         50011,
+		Name:          "metadata.v1.tags",
+		Tag:           "bytes,50011,rep,name=tags",
+		Filename:      "metadata/v1/metadata.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*string)(nil),
+		Field:         50012,
+		Name:          "metadata.v1.deprecation_reason",
+		Tag:           "bytes,50012,opt,name=deprecation_reason",
+		Filename:      "metadata/v1/metadata.proto",
+	},
 	{
 		ExtendedType:  (*descriptorpb.MessageOptions)(nil),
 		ExtensionType: (*bool)(nil),
@@ -70,6 +250,62 @@ var file_metadata_v1_metadata_proto_extTypes = []protoimpl.ExtensionInfo{
 		Tag:           "bytes,50002,rep,name=provides",
 		Filename:      "metadata/v1/metadata.proto",
 	},
+	{
+		ExtendedType:  (*descriptorpb.MessageOptions)(nil),
+		ExtensionType: ([]*EntityKey)(nil),
+		Field:         50003,
+		Name:          "metadata.v1.keys",
+		Tag:           "bytes,50003,rep,name=keys",
+		Filename:      "metadata/v1/metadata.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.MessageOptions)(nil),
+		ExtensionType: (*bool)(nil),
+		Field:         50004,
+		Name:          "metadata.v1.type_shareable",
+		Tag:           "varint,50004,opt,name=type_shareable",
+		Filename:      "metadata/v1/metadata.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.MessageOptions)(nil),
+		ExtensionType: (*bool)(nil),
+		Field:         50005,
+		Name:          "metadata.v1.type_inaccessible",
+		Tag:           "varint,50005,opt,name=type_inaccessible",
+		Filename:      "metadata/v1/metadata.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.MessageOptions)(nil),
+		ExtensionType: ([]string)(nil),
+		Field:         50006,
+		Name:          "metadata.v1.type_tags",
+		Tag:           "bytes,50006,rep,name=type_tags",
+		Filename:      "metadata/v1/metadata.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.MessageOptions)(nil),
+		ExtensionType: (*bool)(nil),
+		Field:         50007,
+		Name:          "metadata.v1.interface_object",
+		Tag:           "varint,50007,opt,name=interface_object",
+		Filename:      "metadata/v1/metadata.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.MessageOptions)(nil),
+		ExtensionType: (*string)(nil),
+		Field:         50008,
+		Name:          "metadata.v1.type_deprecation_reason",
+		Tag:           "bytes,50008,opt,name=type_deprecation_reason",
+		Filename:      "metadata/v1/metadata.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.MessageOptions)(nil),
+		ExtensionType: (*string)(nil),
+		Field:         50009,
+		Name:          "metadata.v1.type_name",
+		Tag:           "bytes,50009,opt,name=type_name",
+		Filename:      "metadata/v1/metadata.proto",
+	},
 	{
 		ExtendedType:  (*descriptorpb.ServiceOptions)(nil),
 		ExtensionType: (*bool)(nil),
@@ -86,6 +322,46 @@ var file_metadata_v1_metadata_proto_extTypes = []protoimpl.ExtensionInfo{
 		Tag:           "bytes,50002,opt,name=service_name",
 		Filename:      "metadata/v1/metadata.proto",
 	},
+	{
+		ExtendedType:  (*descriptorpb.MethodOptions)(nil),
+		ExtensionType: (*OperationType)(nil),
+		Field:         50001,
+		Name:          "metadata.v1.operation",
+		Tag:           "varint,50001,opt,name=operation,enum=metadata.v1.OperationType",
+		Filename:      "metadata/v1/metadata.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.MethodOptions)(nil),
+		ExtensionType: (*string)(nil),
+		Field:         50002,
+		Name:          "metadata.v1.operation_name",
+		Tag:           "bytes,50002,opt,name=operation_name",
+		Filename:      "metadata/v1/metadata.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.MethodOptions)(nil),
+		ExtensionType: (*string)(nil),
+		Field:         50003,
+		Name:          "metadata.v1.operation_deprecation_reason",
+		Tag:           "bytes,50003,opt,name=operation_deprecation_reason",
+		Filename:      "metadata/v1/metadata.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.EnumOptions)(nil),
+		ExtensionType: (*string)(nil),
+		Field:         50001,
+		Name:          "metadata.v1.enum_name",
+		Tag:           "bytes,50001,opt,name=enum_name",
+		Filename:      "metadata/v1/metadata.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.EnumValueOptions)(nil),
+		ExtensionType: (*string)(nil),
+		Field:         50001,
+		Name:          "metadata.v1.value_deprecation_reason",
+		Tag:           "bytes,50001,opt,name=value_deprecation_reason",
+		Filename:      "metadata/v1/metadata.proto",
+	},
 }
 
 // Extension fields to descriptorpb.FieldOptions.
@@ -111,6 +387,46 @@ var (
 	//
 	// optional string computed_from = 50004;
 	E_ComputedFrom = &file_metadata_v1_metadata_proto_extTypes[3]
+	// Overrides the name of this field in the GraphQL schema
+	// If not provided, the name is derived using the generator's naming option
+	//
+	// optional string field_name = 50005;
+	E_FieldName = &file_metadata_v1_metadata_proto_extTypes[4]
+	// Marks this field as the payload of a response message
+	// Operations returning the message return this field's type instead
+	//
+	// optional bool payload = 50006;
+	E_Payload = &file_metadata_v1_metadata_proto_extTypes[5]
+	// Specifies the fields of the referenced entity this field can resolve
+	// For GraphQL federation, this corresponds to the @provides directive
+	//
+	// optional string provides_fields = 50007;
+	E_ProvidesFields = &file_metadata_v1_metadata_proto_extTypes[6]
+	// Marks this field as resolvable by multiple subgraphs
+	// For GraphQL federation, this corresponds to the @shareable directive
+	//
+	// optional bool shareable = 50008;
+	E_Shareable = &file_metadata_v1_metadata_proto_extTypes[7]
+	// Hides this field from the supergraph's API schema
+	// For GraphQL federation, this corresponds to the @inaccessible directive
+	//
+	// optional bool inaccessible = 50009;
+	E_Inaccessible = &file_metadata_v1_metadata_proto_extTypes[8]
+	// Names the subgraph this field is migrated from
+	// For GraphQL federation, this corresponds to the @override directive
+	//
+	// optional string override_from = 50010;
+	E_OverrideFrom = &file_metadata_v1_metadata_proto_extTypes[9]
+	// Tags this field for contracts, one @tag directive per tag
+	// For GraphQL federation, this corresponds to the @tag directive
+	//
+	// repeated string tags = 50011;
+	E_Tags = &file_metadata_v1_metadata_proto_extTypes[10]
+	// Explains why this field is deprecated and marks it deprecated
+	// For GraphQL, this is the reason of the @deprecated directive
+	//
+	// optional string deprecation_reason = 50012;
+	E_DeprecationReason = &file_metadata_v1_metadata_proto_extTypes[11]
 )
 
 // Extension fields to descriptorpb.MessageOptions.
@@ -121,12 +437,49 @@ var (
 	// For databases, this could represent a table or document type
 	//
 	// optional bool entity = 50001;
-	E_Entity = &file_metadata_v1_metadata_proto_extTypes[4]
-	// Specifies the resolvers for this entity in other services
-	// For GraphQL federation, this helps with proper reference resolution
+	E_Entity = &file_metadata_v1_metadata_proto_extTypes[12]
+	// Specifies the methods that resolve this entity by its key
+	// For GraphQL federation, these back the entity's reference resolver
 	//
 	// repeated string provides = 50002;
-	E_Provides = &file_metadata_v1_metadata_proto_extTypes[5]
+	E_Provides = &file_metadata_v1_metadata_proto_extTypes[13]
+	// Declares the keys of this entity, in addition to the fields marked with
+	// the key field option
+	// For GraphQL federation, each key corresponds to an @key directive
+	//
+	// repeated metadata.v1.EntityKey keys = 50003;
+	E_Keys = &file_metadata_v1_metadata_proto_extTypes[14]
+	// Marks every field of this type as resolvable by multiple subgraphs
+	// For GraphQL federation, this corresponds to the @shareable directive
+	//
+	// optional bool type_shareable = 50004;
+	E_TypeShareable = &file_metadata_v1_metadata_proto_extTypes[15]
+	// Hides this type from the supergraph's API schema
+	// For GraphQL federation, this corresponds to the @inaccessible directive
+	//
+	// optional bool type_inaccessible = 50005;
+	E_TypeInaccessible = &file_metadata_v1_metadata_proto_extTypes[16]
+	// Tags this type for contracts, one @tag directive per tag
+	// For GraphQL federation, this corresponds to the @tag directive
+	//
+	// repeated string type_tags = 50006;
+	E_TypeTags = &file_metadata_v1_metadata_proto_extTypes[17]
+	// Marks this entity as an interface defined in another subgraph
+	// For GraphQL federation, this corresponds to the @interfaceObject directive
+	//
+	// optional bool interface_object = 50007;
+	E_InterfaceObject = &file_metadata_v1_metadata_proto_extTypes[18]
+	// Explains why this message is deprecated and marks it deprecated
+	// For GraphQL, fields and operations returning the type are @deprecated
+	//
+	// optional string type_deprecation_reason = 50008;
+	E_TypeDeprecationReason = &file_metadata_v1_metadata_proto_extTypes[19]
+	// Overrides the name of this message's type in the GraphQL schema
+	// If not provided, the message name is used, qualified with its package if
+	// it collides with another type
+	//
+	// optional string type_name = 50009;
+	E_TypeName = &file_metadata_v1_metadata_proto_extTypes[20]
 )
 
 // Extension fields to descriptorpb.ServiceOptions.
@@ -134,12 +487,52 @@ var (
 	// Indicates this service should be included in the federated graph
 	//
 	// optional bool federated = 50001;
-	E_Federated = &file_metadata_v1_metadata_proto_extTypes[6]
+	E_Federated = &file_metadata_v1_metadata_proto_extTypes[21]
 	// Specifies the service name in the federation
 	// If not provided, the proto service name will be used
 	//
 	// optional string service_name = 50002;
-	E_ServiceName = &file_metadata_v1_metadata_proto_extTypes[7]
+	E_ServiceName = &file_metadata_v1_metadata_proto_extTypes[22]
+)
+
+// Extension fields to descriptorpb.MethodOptions.
+var (
+	// Sets the GraphQL operation type this method is exposed as
+	// If not provided, NO_SIDE_EFFECTS methods are queries, server-streaming
+	// methods are subscriptions and other methods are classified by name
+	//
+	// optional metadata.v1.OperationType operation = 50001;
+	E_Operation = &file_metadata_v1_metadata_proto_extTypes[23]
+	// Overrides the name of the GraphQL operation for this method
+	// If not provided, the name is derived using the generator's
+	// operation_naming option
+	//
+	// optional string operation_name = 50002;
+	E_OperationName = &file_metadata_v1_metadata_proto_extTypes[24]
+	// Explains why this method is deprecated and marks it deprecated
+	// For GraphQL, this is the reason of the operation's @deprecated directive
+	//
+	// optional string operation_deprecation_reason = 50003;
+	E_OperationDeprecationReason = &file_metadata_v1_metadata_proto_extTypes[25]
+)
+
+// Extension fields to descriptorpb.EnumOptions.
+var (
+	// Overrides the name of this enum in the GraphQL schema
+	// If not provided, the enum name is used, qualified with its package if it
+	// collides with another type
+	//
+	// optional string enum_name = 50001;
+	E_EnumName = &file_metadata_v1_metadata_proto_extTypes[26]
+)
+
+// Extension fields to descriptorpb.EnumValueOptions.
+var (
+	// Explains why this value is deprecated and marks it deprecated
+	// For GraphQL, this is the reason of the value's @deprecated directive
+	//
+	// optional string value_deprecation_reason = 50001;
+	E_ValueDeprecationReason = &file_metadata_v1_metadata_proto_extTypes[27]
 )
 
 var File_metadata_v1_metadata_proto protoreflect.FileDescriptor
@@ -149,71 +542,219 @@ var file_metadata_v1_metadata_proto_rawDesc = string([]byte{
 	0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0b, 0x6d, 0x65,
 	0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x76, 0x31, 0x1a, 0x20, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
 	0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x65, 0x73, 0x63, 0x72,
-	0x69, 0x70, 0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x3a, 0x31, 0x0a, 0x03, 0x6b,
-	0x65, 0x79, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e,
-	0x73, 0x18, 0xd1, 0x86, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x3a, 0x3b,
-	0x0a, 0x08, 0x65, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x6f,
+	0x69, 0x70, 0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x57, 0x0a, 0x09, 0x45,
+	0x6e, 0x74, 0x69, 0x74, 0x79, 0x4b, 0x65, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c,
+	0x64, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73,
+	0x12, 0x23, 0x0a, 0x0a, 0x72, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x0a, 0x72, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x61, 0x62,
+	0x6c, 0x65, 0x88, 0x01, 0x01, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x72, 0x65, 0x73, 0x6f, 0x6c, 0x76,
+	0x61, 0x62, 0x6c, 0x65, 0x2a, 0x87, 0x01, 0x0a, 0x0d, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1e, 0x0a, 0x1a, 0x4f, 0x50, 0x45, 0x52, 0x41, 0x54,
+	0x49, 0x4f, 0x4e, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49,
+	0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x18, 0x0a, 0x14, 0x4f, 0x50, 0x45, 0x52, 0x41, 0x54,
+	0x49, 0x4f, 0x4e, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x51, 0x55, 0x45, 0x52, 0x59, 0x10, 0x01,
+	0x12, 0x1b, 0x0a, 0x17, 0x4f, 0x50, 0x45, 0x52, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x54, 0x59,
+	0x50, 0x45, 0x5f, 0x4d, 0x55, 0x54, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0x02, 0x12, 0x1f, 0x0a,
+	0x1b, 0x4f, 0x50, 0x45, 0x52, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f,
+	0x53, 0x55, 0x42, 0x53, 0x43, 0x52, 0x49, 0x50, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0x03, 0x3a, 0x31,
+	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd1, 0x86, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x3a, 0x3b, 0x0a, 0x08, 0x65, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x12, 0x1d, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd2, 0x86, 0x03,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x65, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x3a, 0x3b,
+	0x0a, 0x08, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x73, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x6f,
 	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x65,
-	0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd2, 0x86, 0x03, 0x20, 0x01, 0x28,
-	0x08, 0x52, 0x08, 0x65, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x3a, 0x3b, 0x0a, 0x08, 0x72,
-	0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x73, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f,
-	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd3, 0x86, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
-	0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x73, 0x3a, 0x44, 0x0a, 0x0d, 0x63, 0x6f, 0x6d, 0x70,
-	0x75, 0x74, 0x65, 0x64, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
-	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c,
-	0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd4, 0x86, 0x03, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x0c, 0x63, 0x6f, 0x6d, 0x70, 0x75, 0x74, 0x65, 0x64, 0x46, 0x72, 0x6f, 0x6d, 0x3a, 0x39,
-	0x0a, 0x06, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x1f, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
-	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61,
-	0x67, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd1, 0x86, 0x03, 0x20, 0x01, 0x28,
-	0x08, 0x52, 0x06, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x3a, 0x3d, 0x0a, 0x08, 0x70, 0x72, 0x6f,
-	0x76, 0x69, 0x64, 0x65, 0x73, 0x12, 0x1f, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd3, 0x86, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x73, 0x3a, 0x44, 0x0a, 0x0d, 0x63,
+	0x6f, 0x6d, 0x70, 0x75, 0x74, 0x65, 0x64, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x1d, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46,
+	0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd4, 0x86, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0c, 0x63, 0x6f, 0x6d, 0x70, 0x75, 0x74, 0x65, 0x64, 0x46, 0x72, 0x6f,
+	0x6d, 0x3a, 0x3e, 0x0a, 0x0a, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd5,
+	0x86, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x4e, 0x61, 0x6d,
+	0x65, 0x3a, 0x39, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x1d, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46,
+	0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd6, 0x86, 0x03, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x3a, 0x48, 0x0a, 0x0f,
+	0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x73, 0x5f, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x12,
+	0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd7,
+	0x86, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x73,
+	0x46, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x3a, 0x3d, 0x0a, 0x09, 0x73, 0x68, 0x61, 0x72, 0x65, 0x61,
+	0x62, 0x6c, 0x65, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x18, 0xd8, 0x86, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x73, 0x68, 0x61, 0x72,
+	0x65, 0x61, 0x62, 0x6c, 0x65, 0x3a, 0x43, 0x0a, 0x0c, 0x69, 0x6e, 0x61, 0x63, 0x63, 0x65, 0x73,
+	0x73, 0x69, 0x62, 0x6c, 0x65, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd9, 0x86, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x69, 0x6e,
+	0x61, 0x63, 0x63, 0x65, 0x73, 0x73, 0x69, 0x62, 0x6c, 0x65, 0x3a, 0x44, 0x0a, 0x0d, 0x6f, 0x76,
+	0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x1d, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69,
+	0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xda, 0x86, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0c, 0x6f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x46, 0x72, 0x6f, 0x6d,
+	0x3a, 0x33, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64,
+	0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xdb, 0x86, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x04, 0x74, 0x61, 0x67, 0x73, 0x3a, 0x4e, 0x0a, 0x12, 0x64, 0x65, 0x70, 0x72, 0x65, 0x63, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x1d, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69,
+	0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xdc, 0x86, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x11, 0x64, 0x65, 0x70, 0x72, 0x65, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x65, 0x61, 0x73, 0x6f, 0x6e, 0x3a, 0x39, 0x0a, 0x06, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12,
+	0x1f, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x18, 0xd1, 0x86, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79,
+	0x3a, 0x3d, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x73, 0x12, 0x1f, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd2, 0x86,
+	0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x73, 0x3a,
+	0x4d, 0x0a, 0x04, 0x6b, 0x65, 0x79, 0x73, 0x12, 0x1f, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd3, 0x86, 0x03, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x16, 0x2e, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x45,
+	0x6e, 0x74, 0x69, 0x74, 0x79, 0x4b, 0x65, 0x79, 0x52, 0x04, 0x6b, 0x65, 0x79, 0x73, 0x3a, 0x48,
+	0x0a, 0x0e, 0x74, 0x79, 0x70, 0x65, 0x5f, 0x73, 0x68, 0x61, 0x72, 0x65, 0x61, 0x62, 0x6c, 0x65,
+	0x12, 0x1f, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x18, 0xd4, 0x86, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x74, 0x79, 0x70, 0x65, 0x53,
+	0x68, 0x61, 0x72, 0x65, 0x61, 0x62, 0x6c, 0x65, 0x3a, 0x4e, 0x0a, 0x11, 0x74, 0x79, 0x70, 0x65,
+	0x5f, 0x69, 0x6e, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73, 0x69, 0x62, 0x6c, 0x65, 0x12, 0x1f, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd5,
+	0x86, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x10, 0x74, 0x79, 0x70, 0x65, 0x49, 0x6e, 0x61, 0x63,
+	0x63, 0x65, 0x73, 0x73, 0x69, 0x62, 0x6c, 0x65, 0x3a, 0x3e, 0x0a, 0x09, 0x74, 0x79, 0x70, 0x65,
+	0x5f, 0x74, 0x61, 0x67, 0x73, 0x12, 0x1f, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
 	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x4f,
-	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd2, 0x86, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08,
-	0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x73, 0x3a, 0x3f, 0x0a, 0x09, 0x66, 0x65, 0x64, 0x65,
-	0x72, 0x61, 0x74, 0x65, 0x64, 0x12, 0x1f, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x4f,
-	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd1, 0x86, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09,
-	0x66, 0x65, 0x64, 0x65, 0x72, 0x61, 0x74, 0x65, 0x64, 0x3a, 0x44, 0x0a, 0x0c, 0x73, 0x65, 0x72,
-	0x76, 0x69, 0x63, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1f, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
-	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x65, 0x72, 0x76,
-	0x69, 0x63, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd2, 0x86, 0x03, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x0b, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x42,
-	0xb5, 0x01, 0x0a, 0x0f, 0x63, 0x6f, 0x6d, 0x2e, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
-	0x2e, 0x76, 0x31, 0x42, 0x0d, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x50, 0x72, 0x6f,
-	0x74, 0x6f, 0x50, 0x01, 0x5a, 0x46, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
-	0x2f, 0x66, 0x72, 0x61, 0x73, 0x65, 0x72, 0x2d, 0x69, 0x73, 0x62, 0x65, 0x73, 0x74, 0x65, 0x72,
-	0x2f, 0x66, 0x65, 0x64, 0x65, 0x72, 0x61, 0x74, 0x65, 0x64, 0x2d, 0x67, 0x71, 0x6c, 0x2f, 0x67,
-	0x65, 0x6e, 0x2f, 0x67, 0x6f, 0x2f, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x2f, 0x76,
-	0x31, 0x3b, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x76, 0x31, 0xa2, 0x02, 0x03, 0x4d,
-	0x58, 0x58, 0xaa, 0x02, 0x0b, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x56, 0x31,
-	0xca, 0x02, 0x0b, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x5c, 0x56, 0x31, 0xe2, 0x02,
-	0x17, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x5c, 0x56, 0x31, 0x5c, 0x47, 0x50, 0x42,
-	0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0xea, 0x02, 0x0c, 0x4d, 0x65, 0x74, 0x61, 0x64,
-	0x61, 0x74, 0x61, 0x3a, 0x3a, 0x56, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd6, 0x86, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08,
+	0x74, 0x79, 0x70, 0x65, 0x54, 0x61, 0x67, 0x73, 0x3a, 0x4c, 0x0a, 0x10, 0x69, 0x6e, 0x74, 0x65,
+	0x72, 0x66, 0x61, 0x63, 0x65, 0x5f, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x1f, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd7, 0x86,
+	0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65,
+	0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x3a, 0x59, 0x0a, 0x17, 0x74, 0x79, 0x70, 0x65, 0x5f, 0x64,
+	0x65, 0x70, 0x72, 0x65, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f,
+	0x6e, 0x12, 0x1f, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x18, 0xd8, 0x86, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x15, 0x74, 0x79, 0x70, 0x65,
+	0x44, 0x65, 0x70, 0x72, 0x65, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x61, 0x73, 0x6f,
+	0x6e, 0x3a, 0x3e, 0x0a, 0x09, 0x74, 0x79, 0x70, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1f,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18,
+	0xd9, 0x86, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x79, 0x70, 0x65, 0x4e, 0x61, 0x6d,
+	0x65, 0x3a, 0x3f, 0x0a, 0x09, 0x66, 0x65, 0x64, 0x65, 0x72, 0x61, 0x74, 0x65, 0x64, 0x12, 0x1f,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18,
+	0xd1, 0x86, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x66, 0x65, 0x64, 0x65, 0x72, 0x61, 0x74,
+	0x65, 0x64, 0x3a, 0x44, 0x0a, 0x0c, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x6e, 0x61,
+	0x6d, 0x65, 0x12, 0x1f, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x4f, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x18, 0xd2, 0x86, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x73, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x3a, 0x5a, 0x0a, 0x09, 0x6f, 0x70, 0x65, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1e, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x4f, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd1, 0x86, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e,
+	0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x70, 0x65, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x09, 0x6f, 0x70, 0x65, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x3a, 0x47, 0x0a, 0x0e, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1e, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x4f,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd2, 0x86, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d,
+	0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4e, 0x61, 0x6d, 0x65, 0x3a, 0x62, 0x0a,
+	0x1c, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x64, 0x65, 0x70, 0x72, 0x65,
+	0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x1e, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd3, 0x86,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x1a, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x44, 0x65, 0x70, 0x72, 0x65, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x61, 0x73, 0x6f,
+	0x6e, 0x3a, 0x3b, 0x0a, 0x09, 0x65, 0x6e, 0x75, 0x6d, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1c,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x45, 0x6e, 0x75, 0x6d, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd1, 0x86, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x65, 0x6e, 0x75, 0x6d, 0x4e, 0x61, 0x6d, 0x65, 0x3a, 0x5d,
+	0x0a, 0x18, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x5f, 0x64, 0x65, 0x70, 0x72, 0x65, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x21, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6e, 0x75,
+	0x6d, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd1, 0x86,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x16, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x44, 0x65, 0x70, 0x72,
+	0x65, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x42, 0xb5, 0x01,
+	0x0a, 0x0f, 0x63, 0x6f, 0x6d, 0x2e, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x76,
+	0x31, 0x42, 0x0d, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x50, 0x72, 0x6f, 0x74, 0x6f,
+	0x50, 0x01, 0x5a, 0x46, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x66,
+	0x72, 0x61, 0x73, 0x65, 0x72, 0x2d, 0x69, 0x73, 0x62, 0x65, 0x73, 0x74, 0x65, 0x72, 0x2f, 0x66,
+	0x65, 0x64, 0x65, 0x72, 0x61, 0x74, 0x65, 0x64, 0x2d, 0x67, 0x71, 0x6c, 0x2f, 0x67, 0x65, 0x6e,
+	0x2f, 0x67, 0x6f, 0x2f, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x2f, 0x76, 0x31, 0x3b,
+	0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x76, 0x31, 0xa2, 0x02, 0x03, 0x4d, 0x58, 0x58,
+	0xaa, 0x02, 0x0b, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x56, 0x31, 0xca, 0x02,
+	0x0b, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x5c, 0x56, 0x31, 0xe2, 0x02, 0x17, 0x4d,
+	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x5c, 0x56, 0x31, 0x5c, 0x47, 0x50, 0x42, 0x4d, 0x65,
+	0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0xea, 0x02, 0x0c, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74,
+	0x61, 0x3a, 0x3a, 0x56, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 })
 
+var (
+	file_metadata_v1_metadata_proto_rawDescOnce sync.Once
+	file_metadata_v1_metadata_proto_rawDescData []byte
+)
+
+func file_metadata_v1_metadata_proto_rawDescGZIP() []byte {
+	file_metadata_v1_metadata_proto_rawDescOnce.Do(func() {
+		file_metadata_v1_metadata_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_metadata_v1_metadata_proto_rawDesc), len(file_metadata_v1_metadata_proto_rawDesc)))
+	})
+	return file_metadata_v1_metadata_proto_rawDescData
+}
+
+var file_metadata_v1_metadata_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_metadata_v1_metadata_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
 var file_metadata_v1_metadata_proto_goTypes = []any{
-	(*descriptorpb.FieldOptions)(nil),   // 0: google.protobuf.FieldOptions
-	(*descriptorpb.MessageOptions)(nil), // 1: google.protobuf.MessageOptions
-	(*descriptorpb.ServiceOptions)(nil), // 2: google.protobuf.ServiceOptions
+	(OperationType)(0),                    // 0: metadata.v1.OperationType
+	(*EntityKey)(nil),                     // 1: metadata.v1.EntityKey
+	(*descriptorpb.FieldOptions)(nil),     // 2: google.protobuf.FieldOptions
+	(*descriptorpb.MessageOptions)(nil),   // 3: google.protobuf.MessageOptions
+	(*descriptorpb.ServiceOptions)(nil),   // 4: google.protobuf.ServiceOptions
+	(*descriptorpb.MethodOptions)(nil),    // 5: google.protobuf.MethodOptions
+	(*descriptorpb.EnumOptions)(nil),      // 6: google.protobuf.EnumOptions
+	(*descriptorpb.EnumValueOptions)(nil), // 7: google.protobuf.EnumValueOptions
 }
 var file_metadata_v1_metadata_proto_depIdxs = []int32{
-	0, // 0: metadata.v1.key:extendee -> google.protobuf.FieldOptions
-	0, // 1: metadata.v1.external:extendee -> google.protobuf.FieldOptions
-	0, // 2: metadata.v1.requires:extendee -> google.protobuf.FieldOptions
-	0, // 3: metadata.v1.computed_from:extendee -> google.protobuf.FieldOptions
-	1, // 4: metadata.v1.entity:extendee -> google.protobuf.MessageOptions
-	1, // 5: metadata.v1.provides:extendee -> google.protobuf.MessageOptions
-	2, // 6: metadata.v1.federated:extendee -> google.protobuf.ServiceOptions
-	2, // 7: metadata.v1.service_name:extendee -> google.protobuf.ServiceOptions
-	8, // [8:8] is the sub-list for method output_type
-	8, // [8:8] is the sub-list for method input_type
-	8, // [8:8] is the sub-list for extension type_name
-	0, // [0:8] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	2,  // 0: metadata.v1.key:extendee -> google.protobuf.FieldOptions
+	2,  // 1: metadata.v1.external:extendee -> google.protobuf.FieldOptions
+	2,  // 2: metadata.v1.requires:extendee -> google.protobuf.FieldOptions
+	2,  // 3: metadata.v1.computed_from:extendee -> google.protobuf.FieldOptions
+	2,  // 4: metadata.v1.field_name:extendee -> google.protobuf.FieldOptions
+	2,  // 5: metadata.v1.payload:extendee -> google.protobuf.FieldOptions
+	2,  // 6: metadata.v1.provides_fields:extendee -> google.protobuf.FieldOptions
+	2,  // 7: metadata.v1.shareable:extendee -> google.protobuf.FieldOptions
+	2,  // 8: metadata.v1.inaccessible:extendee -> google.protobuf.FieldOptions
+	2,  // 9: metadata.v1.override_from:extendee -> google.protobuf.FieldOptions
+	2,  // 10: metadata.v1.tags:extendee -> google.protobuf.FieldOptions
+	2,  // 11: metadata.v1.deprecation_reason:extendee -> google.protobuf.FieldOptions
+	3,  // 12: metadata.v1.entity:extendee -> google.protobuf.MessageOptions
+	3,  // 13: metadata.v1.provides:extendee -> google.protobuf.MessageOptions
+	3,  // 14: metadata.v1.keys:extendee -> google.protobuf.MessageOptions
+	3,  // 15: metadata.v1.type_shareable:extendee -> google.protobuf.MessageOptions
+	3,  // 16: metadata.v1.type_inaccessible:extendee -> google.protobuf.MessageOptions
+	3,  // 17: metadata.v1.type_tags:extendee -> google.protobuf.MessageOptions
+	3,  // 18: metadata.v1.interface_object:extendee -> google.protobuf.MessageOptions
+	3,  // 19: metadata.v1.type_deprecation_reason:extendee -> google.protobuf.MessageOptions
+	3,  // 20: metadata.v1.type_name:extendee -> google.protobuf.MessageOptions
+	4,  // 21: metadata.v1.federated:extendee -> google.protobuf.ServiceOptions
+	4,  // 22: metadata.v1.service_name:extendee -> google.protobuf.ServiceOptions
+	5,  // 23: metadata.v1.operation:extendee -> google.protobuf.MethodOptions
+	5,  // 24: metadata.v1.operation_name:extendee -> google.protobuf.MethodOptions
+	5,  // 25: metadata.v1.operation_deprecation_reason:extendee -> google.protobuf.MethodOptions
+	6,  // 26: metadata.v1.enum_name:extendee -> google.protobuf.EnumOptions
+	7,  // 27: metadata.v1.value_deprecation_reason:extendee -> google.protobuf.EnumValueOptions
+	1,  // 28: metadata.v1.keys:type_name -> metadata.v1.EntityKey
+	0,  // 29: metadata.v1.operation:type_name -> metadata.v1.OperationType
+	30, // [30:30] is the sub-list for method output_type
+	30, // [30:30] is the sub-list for method input_type
+	28, // [28:30] is the sub-list for extension type_name
+	0,  // [0:28] is the sub-list for extension extendee
+	0,  // [0:0] is the sub-list for field type_name
 }
 
 func init() { file_metadata_v1_metadata_proto_init() }
@@ -221,18 +762,21 @@ func file_metadata_v1_metadata_proto_init() {
 	if File_metadata_v1_metadata_proto != nil {
 		return
 	}
+	file_metadata_v1_metadata_proto_msgTypes[0].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_metadata_v1_metadata_proto_rawDesc), len(file_metadata_v1_metadata_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   0,
-			NumExtensions: 8,
+			NumEnums:      1,
+			NumMessages:   1,
+			NumExtensions: 28,
 			NumServices:   0,
 		},
 		GoTypes:           file_metadata_v1_metadata_proto_goTypes,
 		DependencyIndexes: file_metadata_v1_metadata_proto_depIdxs,
+		EnumInfos:         file_metadata_v1_metadata_proto_enumTypes,
+		MessageInfos:      file_metadata_v1_metadata_proto_msgTypes,
 		ExtensionInfos:    file_metadata_v1_metadata_proto_extTypes,
 	}.Build()
 	File_metadata_v1_metadata_proto = out.File
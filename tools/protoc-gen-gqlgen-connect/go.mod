@@ -0,0 +1,18 @@
+module github.com/fraser-isbester/federated-gql/tools/protoc-gen-gqlgen-connect
+
+go 1.24.0
+
+replace (
+	github.com/fraser-isbester/federated-gql/gen/go => ../../gen/go
+	github.com/fraser-isbester/federated-gql/tools/protoc-gen-graphql => ../protoc-gen-graphql
+)
+
+require (
+	github.com/fraser-isbester/federated-gql/tools/protoc-gen-graphql v0.0.0-00010101000000-000000000000
+	google.golang.org/protobuf v1.36.5
+)
+
+require (
+	github.com/fraser-isbester/federated-gql/gen/go v0.0.0-00010101000000-000000000000 // indirect
+	google.golang.org/genproto/googleapis/api v0.0.0-20250218202821-56aae31c358a // indirect
+)
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/fraser-isbester/federated-gql/tools/protoc-gen-graphql/generator"
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+func main() {
+	log.SetPrefix("protoc-gen-gqlgen-connect: ")
+	log.SetFlags(0)
+	log.SetOutput(os.Stderr)
+	log.Println("Starting protoc-gen-gqlgen-connect...")
+	var flags flag.FlagSet
+
+	// The resolvers are generated for the schemas protoc-gen-graphql
+	// produces, so the plugin takes the same options
+	opts := generator.Options{}
+	opts.RegisterFlags(&flags)
+
+	protogen.Options{
+		ParamFunc: flags.Set,
+	}.Run(func(gen *protogen.Plugin) error {
+		g, err := generator.NewGenerator(opts)
+		if err != nil {
+			log.Fatalf("failed to create generator: %v", err)
+			return err
+		}
+		return g.GenerateResolvers(gen)
+	})
+}
@@ -0,0 +1,199 @@
+package generator
+
+import (
+	"log"
+
+	metadatav1 "github.com/fraser-isbester/federated-gql/gen/go/metadata/v1"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// FieldAnnotations holds the metadata.v1 options set on a field
+type FieldAnnotations struct {
+	Key          bool
+	External     bool
+	Requires     string
+	ComputedFrom string
+	FieldName    string
+	Payload      bool
+	Provides     string
+	Shareable    bool
+	Inaccessible bool
+	OverrideFrom string
+	Tags         []string
+
+	DeprecationReason string
+}
+
+// MessageAnnotations holds the metadata.v1 options set on a message
+type MessageAnnotations struct {
+	Entity          bool
+	Provides        []string
+	Keys            []*metadatav1.EntityKey
+	Shareable       bool
+	Inaccessible    bool
+	Tags            []string
+	InterfaceObject bool
+	TypeName        string
+
+	DeprecationReason string
+}
+
+// MethodAnnotations holds the metadata.v1 options set on a method
+type MethodAnnotations struct {
+	Operation         metadatav1.OperationType
+	OperationName     string
+	DeprecationReason string
+}
+
+// EnumAnnotations holds the metadata.v1 options set on an enum
+type EnumAnnotations struct {
+	Name string
+}
+
+// EnumValueAnnotations holds the metadata.v1 options set on an enum value
+type EnumValueAnnotations struct {
+	DeprecationReason string
+}
+
+// ServiceAnnotations holds the metadata.v1 options set on a service
+type ServiceAnnotations struct {
+	// Nil unless (metadata.v1.federated) is set explicitly
+	Federated   *bool
+	ServiceName string
+}
+
+// fieldAnnotations reads the metadata.v1 field options of f
+func fieldAnnotations(f *protogen.Field) FieldAnnotations {
+	if f == nil || f.Desc == nil {
+		return FieldAnnotations{}
+	}
+	opts := f.Desc.Options()
+	return FieldAnnotations{
+		Key:          getExtension(opts, metadatav1.E_Key).(bool),
+		External:     getExtension(opts, metadatav1.E_External).(bool),
+		Requires:     getExtension(opts, metadatav1.E_Requires).(string),
+		ComputedFrom: getExtension(opts, metadatav1.E_ComputedFrom).(string),
+		FieldName:    getExtension(opts, metadatav1.E_FieldName).(string),
+		Payload:      getExtension(opts, metadatav1.E_Payload).(bool),
+		Provides:     getExtension(opts, metadatav1.E_ProvidesFields).(string),
+		Shareable:    getExtension(opts, metadatav1.E_Shareable).(bool),
+		Inaccessible: getExtension(opts, metadatav1.E_Inaccessible).(bool),
+		OverrideFrom: getExtension(opts, metadatav1.E_OverrideFrom).(string),
+		Tags:         getExtension(opts, metadatav1.E_Tags).([]string),
+
+		DeprecationReason: getExtension(opts, metadatav1.E_DeprecationReason).(string),
+	}
+}
+
+// messageAnnotations reads the metadata.v1 message options of msg
+func messageAnnotations(msg *protogen.Message) MessageAnnotations {
+	if msg == nil || msg.Desc == nil {
+		return MessageAnnotations{}
+	}
+	opts := msg.Desc.Options()
+	return MessageAnnotations{
+		Entity:          getExtension(opts, metadatav1.E_Entity).(bool),
+		Provides:        getExtension(opts, metadatav1.E_Provides).([]string),
+		Keys:            getExtension(opts, metadatav1.E_Keys).([]*metadatav1.EntityKey),
+		Shareable:       getExtension(opts, metadatav1.E_TypeShareable).(bool),
+		Inaccessible:    getExtension(opts, metadatav1.E_TypeInaccessible).(bool),
+		Tags:            getExtension(opts, metadatav1.E_TypeTags).([]string),
+		InterfaceObject: getExtension(opts, metadatav1.E_InterfaceObject).(bool),
+		TypeName:        getExtension(opts, metadatav1.E_TypeName).(string),
+
+		DeprecationReason: getExtension(opts, metadatav1.E_TypeDeprecationReason).(string),
+	}
+}
+
+// methodAnnotations reads the metadata.v1 method options of method
+func methodAnnotations(method *protogen.Method) MethodAnnotations {
+	if method == nil || method.Desc == nil {
+		return MethodAnnotations{}
+	}
+	opts := method.Desc.Options()
+	return MethodAnnotations{
+		Operation:         getExtension(opts, metadatav1.E_Operation).(metadatav1.OperationType),
+		OperationName:     getExtension(opts, metadatav1.E_OperationName).(string),
+		DeprecationReason: getExtension(opts, metadatav1.E_OperationDeprecationReason).(string),
+	}
+}
+
+// enumAnnotations reads the metadata.v1 enum options of e
+func enumAnnotations(e *protogen.Enum) EnumAnnotations {
+	if e == nil || e.Desc == nil {
+		return EnumAnnotations{}
+	}
+	return EnumAnnotations{
+		Name: getExtension(e.Desc.Options(), metadatav1.E_EnumName).(string),
+	}
+}
+
+// enumValueAnnotations reads the metadata.v1 enum value options of v
+func enumValueAnnotations(v *protogen.EnumValue) EnumValueAnnotations {
+	if v == nil || v.Desc == nil {
+		return EnumValueAnnotations{}
+	}
+	return EnumValueAnnotations{
+		DeprecationReason: getExtension(v.Desc.Options(), metadatav1.E_ValueDeprecationReason).(string),
+	}
+}
+
+// serviceAnnotations reads the metadata.v1 service options of svc
+func serviceAnnotations(svc *protogen.Service) ServiceAnnotations {
+	if svc == nil || svc.Desc == nil {
+		return ServiceAnnotations{}
+	}
+	opts := svc.Desc.Options()
+	annotations := ServiceAnnotations{
+		ServiceName: getExtension(opts, metadatav1.E_ServiceName).(string),
+	}
+	if v, ok := lookupExtension(opts, metadatav1.E_Federated); ok {
+		federated := v.(bool)
+		annotations.Federated = &federated
+	}
+	return annotations
+}
+
+// getExtension returns the value of the extension xt set on opts, or its zero
+// value if it is not set
+func getExtension(opts proto.Message, xt protoreflect.ExtensionType) interface{} {
+	if v, ok := lookupExtension(opts, xt); ok {
+		return v
+	}
+	return xt.InterfaceOf(xt.Zero())
+}
+
+// lookupExtension returns the value of the extension xt and whether it is
+// set on opts.
+//
+// Options that were decoded without the extension being linked into the
+// binary keep the value as unknown fields, and options decoded against a
+// dynamic copy of the extension hold values of the wrong Go type, so when
+// the extension isn't found directly the options are re-encoded and decoded
+// again against the registered extension types.
+func lookupExtension(opts proto.Message, xt protoreflect.ExtensionType) (interface{}, bool) {
+	if opts == nil || !opts.ProtoReflect().IsValid() {
+		return nil, false
+	}
+	if proto.HasExtension(opts, xt) {
+		if v := opts.ProtoReflect().Get(xt.TypeDescriptor()); xt.IsValidValue(v) {
+			return xt.InterfaceOf(v), true
+		}
+	}
+
+	b, err := proto.Marshal(opts)
+	if err != nil || len(b) == 0 {
+		return nil, false
+	}
+	resolved := opts.ProtoReflect().New().Interface()
+	if err := proto.Unmarshal(b, resolved); err != nil {
+		log.Printf("Failed to resolve extension %s: %v", xt.TypeDescriptor().FullName(), err)
+		return nil, false
+	}
+	if proto.HasExtension(resolved, xt) {
+		return proto.GetExtension(resolved, xt), true
+	}
+	return nil, false
+}
@@ -0,0 +1,44 @@
+package generator
+
+import (
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// hideMarker hides the rest of a comment from the generated schema
+const hideMarker = "@graphql-hide"
+
+// description returns the GraphQL description for a proto element: its
+// detached, leading and trailing comments, in source order, as paragraphs.
+// Only the comment markers are stripped, so the text is kept as written.
+// Lines from one containing @graphql-hide to the end of their comment are
+// left out, and """ is escaped so the text can go in a block string.
+func description(comments protogen.CommentSet) string {
+	var paragraphs []string
+	for _, c := range comments.LeadingDetached {
+		paragraphs = appendComment(paragraphs, c)
+	}
+	paragraphs = appendComment(paragraphs, comments.Leading)
+	paragraphs = appendComment(paragraphs, comments.Trailing)
+	return strings.ReplaceAll(strings.Join(paragraphs, "\n\n"), `"""`, `\"""`)
+}
+
+// appendComment appends the normalized text of c to paragraphs, unless it
+// is empty
+func appendComment(paragraphs []string, c protogen.Comments) []string {
+	var lines []string
+	for _, line := range strings.Split(string(c), "\n") {
+		if strings.Contains(line, hideMarker) {
+			break
+		}
+		// protoc keeps the space following the comment marker
+		lines = append(lines, strings.TrimRight(strings.TrimPrefix(line, " "), " \t\r"))
+	}
+
+	text := strings.Trim(strings.Join(lines, "\n"), "\n")
+	if strings.TrimSpace(text) == "" {
+		return paragraphs
+	}
+	return append(paragraphs, text)
+}
@@ -0,0 +1,169 @@
+package generator
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Field names of AIP-158 paginated requests and responses
+const (
+	pageSizeField      = "page_size"
+	pageTokenField     = "page_token"
+	nextPageTokenField = "next_page_token"
+	totalSizeField     = "total_size"
+)
+
+// Connection is a Relay connection type generated for the items returned
+// by paginated methods
+type Connection struct {
+	Name     string
+	EdgeName string
+	NodeType string
+	// Whether the connection has a totalCount field, set when a response
+	// returning these items has a total_size field
+	TotalCount bool
+}
+
+// Pagination maps a paginated method's Relay connection arguments and
+// fields to the proto fields of its request and response: first is the page
+// size, after the page token, the edges' nodes are the items and the page
+// info is derived from the next page token
+type Pagination struct {
+	Connection *Connection
+	// Proto field names of the request
+	PageSizeField  string
+	PageTokenField string
+	// Proto field names of the response
+	ItemsField         string
+	NextPageTokenField string
+	// Empty unless the response has a total size
+	TotalSizeField string
+
+	pageSize  *protogen.Field
+	pageToken *protogen.Field
+	items     *protogen.Field
+	totalSize *protogen.Field
+}
+
+// paginatedList returns the response field holding the items of a method
+// following AIP-158: a unary method whose request has an int32 page_size
+// and a string page_token, and whose response has a string next_page_token
+// and a single repeated field, or several with the items marked with
+// (metadata.v1.payload). It returns nil for other methods.
+func paginatedList(method *protogen.Method) *protogen.Field {
+	if method.Desc.IsStreamingClient() || method.Desc.IsStreamingServer() {
+		return nil
+	}
+	if !hasScalarField(method.Input, pageSizeField, protoreflect.Int32Kind) ||
+		!hasScalarField(method.Input, pageTokenField, protoreflect.StringKind) ||
+		!hasScalarField(method.Output, nextPageTokenField, protoreflect.StringKind) {
+		return nil
+	}
+
+	var items []*protogen.Field
+	for _, f := range method.Output.Fields {
+		if !f.Desc.IsList() {
+			continue
+		}
+		if fieldAnnotations(f).Payload {
+			return f
+		}
+		items = append(items, f)
+	}
+	if len(items) != 1 {
+		return nil
+	}
+	return items[0]
+}
+
+// hasScalarField reports whether msg has a singular field of the given name
+// and kind
+func hasScalarField(msg *protogen.Message, name string, kind protoreflect.Kind) bool {
+	f := msg.Desc.Fields().ByName(protoreflect.Name(name))
+	return f != nil && f.Kind() == kind && f.Cardinality() != protoreflect.Repeated
+}
+
+// isPageField reports whether f is the page size or page token of a
+// paginated request, which are replaced by the Relay connection arguments
+func (tm *typeMapper) isPageField(f *protogen.Field) bool {
+	return tm.pageFields[f.Desc.FullName()]
+}
+
+// pagination records the mapping of a paginated method to its Relay
+// connection, registering the connection for the method's items the first
+// time they are seen
+func (tm *typeMapper) pagination(method *protogen.Method, items *protogen.Field) *Pagination {
+	p := &Pagination{
+		Connection:         tm.connection(tm.graphQLType(items, outputUse)),
+		PageSizeField:      pageSizeField,
+		PageTokenField:     pageTokenField,
+		ItemsField:         string(items.Desc.Name()),
+		NextPageTokenField: nextPageTokenField,
+		items:              items,
+	}
+	for _, f := range method.Input.Fields {
+		switch string(f.Desc.Name()) {
+		case pageSizeField:
+			p.pageSize = f
+		case pageTokenField:
+			p.pageToken = f
+		}
+	}
+	tm.pageFields[p.pageSize.Desc.FullName()] = true
+	tm.pageFields[p.pageToken.Desc.FullName()] = true
+
+	if hasScalarField(method.Output, totalSizeField, protoreflect.Int32Kind) ||
+		hasScalarField(method.Output, totalSizeField, protoreflect.Int64Kind) {
+		p.TotalSizeField = totalSizeField
+		p.Connection.TotalCount = true
+		for _, f := range method.Output.Fields {
+			if string(f.Desc.Name()) == totalSizeField {
+				p.totalSize = f
+			}
+		}
+	}
+	return p
+}
+
+// connection returns the connection of nodeType, registering it the first
+// time it is seen. Connections are named after their node type with a
+// Connection suffix and their edges with an Edge suffix; if either name is
+// already taken a number is appended to both (ProductConnection2).
+func (tm *typeMapper) connection(nodeType string) *Connection {
+	if conn, ok := tm.seenConnections[nodeType]; ok {
+		return conn
+	}
+
+	conn := &Connection{
+		Name:     nodeType + "Connection",
+		EdgeName: nodeType + "Edge",
+		NodeType: nodeType,
+	}
+	for i := 2; tm.typeNames[conn.Name] || tm.typeNames[conn.EdgeName]; i++ {
+		conn.Name = fmt.Sprintf("%sConnection%d", nodeType, i)
+		conn.EdgeName = fmt.Sprintf("%sEdge%d", nodeType, i)
+	}
+	tm.typeNames[conn.Name] = true
+	tm.typeNames[conn.EdgeName] = true
+
+	tm.seenConnections[nodeType] = conn
+	tm.connections = append(tm.connections, conn)
+	return conn
+}
+
+// Connections returns the connections used so far, in the order they were
+// first referenced
+func (tm *typeMapper) Connections() []*Connection {
+	return tm.connections
+}
+
+// connectionArgs returns the Relay arguments replacing a paginated request's
+// page size and page token
+func connectionArgs(p *Pagination) []*Field {
+	return []*Field{
+		{Name: "first", GraphQLType: "Int", field: p.pageSize},
+		{Name: "after", GraphQLType: "String", field: p.pageToken},
+	}
+}
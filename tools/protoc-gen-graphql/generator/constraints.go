@@ -0,0 +1,207 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// validateExtensions are the field options holding validation rules: the
+// protoc-gen-validate rules and the protovalidate field rules. Both are read
+// dynamically from the descriptors the protos import, so neither has to be
+// linked into the generator, and they share the names of the rules used.
+var validateExtensions = []protoreflect.FullName{
+	"validate.rules",
+	"buf.validate.field",
+}
+
+// Constraint is an argument of the @constraint directive
+type Constraint struct {
+	Name string
+	// The argument value as a GraphQL literal
+	Value string
+}
+
+// stringFormats maps the well-known string rules to @constraint formats
+var stringFormats = []struct {
+	rule   protoreflect.Name
+	format string
+}{
+	{"email", "email"},
+	{"hostname", "hostname"},
+	{"ipv4", "ipv4"},
+	{"ipv6", "ipv6"},
+	{"uri", "uri"},
+	{"uri_ref", "uri-reference"},
+	{"uuid", "uuid"},
+}
+
+// numberRules are the rule sets of the numeric proto types
+var numberRules = map[protoreflect.Name]bool{
+	"float": true, "double": true,
+	"int32": true, "int64": true, "uint32": true, "uint64": true,
+	"sint32": true, "sint64": true, "fixed32": true, "fixed64": true,
+	"sfixed32": true, "sfixed64": true,
+}
+
+// fieldConstraints translates the validation rules of f into @constraint
+// arguments. Rules without a GraphQL equivalent are ignored.
+func fieldConstraints(f *protogen.Field) []*Constraint {
+	for _, name := range validateExtensions {
+		xd := findExtension(f.Desc.ParentFile(), name, make(map[string]bool))
+		if xd == nil {
+			continue
+		}
+		if rules := dynamicExtension(f.Desc.Options(), xd); rules != nil {
+			return ruleConstraints(rules)
+		}
+	}
+	return nil
+}
+
+// findExtension returns the extension called name declared in file or the
+// files it imports, or nil if there is none
+func findExtension(file protoreflect.FileDescriptor, name protoreflect.FullName, seen map[string]bool) protoreflect.ExtensionDescriptor {
+	if seen[file.Path()] {
+		return nil
+	}
+	seen[file.Path()] = true
+	if file.Package() == name.Parent() {
+		if xd := file.Extensions().ByName(name.Name()); xd != nil {
+			return xd
+		}
+	}
+	imports := file.Imports()
+	for i := 0; i < imports.Len(); i++ {
+		if xd := findExtension(imports.Get(i).FileDescriptor, name, seen); xd != nil {
+			return xd
+		}
+	}
+	return nil
+}
+
+// dynamicExtension returns the value of the message extension xd set on
+// opts, or nil if it isn't set. The options are re-encoded and decoded with
+// a dynamic type for the extension, as in getExtension.
+func dynamicExtension(opts proto.Message, xd protoreflect.ExtensionDescriptor) protoreflect.Message {
+	if opts == nil || !opts.ProtoReflect().IsValid() || xd.Message() == nil {
+		return nil
+	}
+	b, err := proto.Marshal(opts)
+	if err != nil || len(b) == 0 {
+		return nil
+	}
+
+	xt := dynamicpb.NewExtensionType(xd)
+	types := new(protoregistry.Types)
+	if err := types.RegisterExtension(xt); err != nil {
+		return nil
+	}
+	resolved := opts.ProtoReflect().New().Interface()
+	if err := (proto.UnmarshalOptions{Resolver: types}).Unmarshal(b, resolved); err != nil {
+		return nil
+	}
+	if !resolved.ProtoReflect().Has(xt.TypeDescriptor()) {
+		return nil
+	}
+	return resolved.ProtoReflect().Get(xt.TypeDescriptor()).Message()
+}
+
+// ruleConstraints translates a FieldRules message into @constraint arguments
+func ruleConstraints(rules protoreflect.Message) []*Constraint {
+	var b constraintBuilder
+	fields := rules.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if fd.Message() == nil || !rules.Has(fd) {
+			continue
+		}
+		typeRules := rules.Get(fd).Message()
+		switch {
+		case fd.Name() == "string":
+			b.add(typeRules, "min_len", "minLength")
+			b.add(typeRules, "max_len", "maxLength")
+			b.add(typeRules, "len", "minLength")
+			b.add(typeRules, "len", "maxLength")
+			b.add(typeRules, "pattern", "pattern")
+			b.add(typeRules, "prefix", "startsWith")
+			b.add(typeRules, "suffix", "endsWith")
+			b.add(typeRules, "contains", "contains")
+			b.add(typeRules, "not_contains", "notContains")
+			for _, format := range stringFormats {
+				if v, ok := ruleValue(typeRules, format.rule); ok && v.Bool() {
+					b.set("format", quote(format.format))
+				}
+			}
+		case fd.Name() == "repeated":
+			b.add(typeRules, "min_items", "minItems")
+			b.add(typeRules, "max_items", "maxItems")
+		case fd.Name() == "map":
+			b.add(typeRules, "min_pairs", "minItems")
+			b.add(typeRules, "max_pairs", "maxItems")
+		case numberRules[fd.Name()]:
+			b.add(typeRules, "gte", "min")
+			b.add(typeRules, "lte", "max")
+			b.add(typeRules, "gt", "exclusiveMin")
+			b.add(typeRules, "lt", "exclusiveMax")
+		}
+	}
+	return b.constraints
+}
+
+// ruleValue returns the value of the rule called name, if it is set
+func ruleValue(rules protoreflect.Message, name protoreflect.Name) (protoreflect.Value, bool) {
+	fd := rules.Descriptor().Fields().ByName(name)
+	if fd == nil || fd.IsList() || !rules.Has(fd) {
+		return protoreflect.Value{}, false
+	}
+	return rules.Get(fd), true
+}
+
+// constraintBuilder collects @constraint arguments, keeping the first value
+// set for each argument
+type constraintBuilder struct {
+	constraints []*Constraint
+}
+
+// add sets the argument name from the rule called rule, if it is set
+func (b *constraintBuilder) add(rules protoreflect.Message, rule protoreflect.Name, name string) {
+	v, ok := ruleValue(rules, rule)
+	if !ok {
+		return
+	}
+	switch v := v.Interface().(type) {
+	case string:
+		b.set(name, quote(v))
+	case int32, int64, uint32, uint64, float32, float64:
+		b.set(name, fmt.Sprint(v))
+	}
+}
+
+// set sets the argument name to the GraphQL literal value
+func (b *constraintBuilder) set(name, value string) {
+	for _, c := range b.constraints {
+		if c.Name == name {
+			return
+		}
+	}
+	b.constraints = append(b.constraints, &Constraint{Name: name, Value: value})
+}
+
+// ConstraintDirective returns the @constraint directive for the field's
+// validation rules, with a leading space, or "" if it has none
+func (f *Field) ConstraintDirective() string {
+	if len(f.Constraints) == 0 {
+		return ""
+	}
+	args := make([]string, len(f.Constraints))
+	for i, c := range f.Constraints {
+		args[i] = c.Name + ": " + c.Value
+	}
+	return " @constraint(" + strings.Join(args, ", ") + ")"
+}
@@ -0,0 +1,780 @@
+package generator
+
+import (
+	"log"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// graphQLKind is the kind of a GraphQL type, which decides its Go type in
+// the gqlgen models
+type graphQLKind int
+
+const (
+	scalarKind graphQLKind = iota
+	objectKind
+	inputKind
+	enumKind
+	unionKind
+)
+
+const (
+	jsonPackage      = protogen.GoImportPath("encoding/json")
+	mapsPackage      = protogen.GoImportPath("maps")
+	slicesPackage    = protogen.GoImportPath("slices")
+	protoPackage     = protogen.GoImportPath("google.golang.org/protobuf/proto")
+	protojsonPackage = protogen.GoImportPath("google.golang.org/protobuf/encoding/protojson")
+	timestampPackage = protogen.GoImportPath("google.golang.org/protobuf/types/known/timestamppb")
+	durationPackage  = protogen.GoImportPath("google.golang.org/protobuf/types/known/durationpb")
+	structPackage    = protogen.GoImportPath("google.golang.org/protobuf/types/known/structpb")
+	anyPackage       = protogen.GoImportPath("google.golang.org/protobuf/types/known/anypb")
+	fieldMaskPackage = protogen.GoImportPath("google.golang.org/protobuf/types/known/fieldmaskpb")
+	emptyPackage     = protogen.GoImportPath("google.golang.org/protobuf/types/known/emptypb")
+	wrappersPackage  = protogen.GoImportPath("google.golang.org/protobuf/types/known/wrapperspb")
+)
+
+// wellKnownGoTypes are the Go types of the well-known types scalars are
+// bound to
+var wellKnownGoTypes = map[string]protogen.GoIdent{
+	"google.protobuf.Timestamp":   timestampPackage.Ident("Timestamp"),
+	"google.protobuf.Duration":    durationPackage.Ident("Duration"),
+	"google.protobuf.FieldMask":   fieldMaskPackage.Ident("FieldMask"),
+	"google.protobuf.Struct":      structPackage.Ident("Struct"),
+	"google.protobuf.Value":       structPackage.Ident("Value"),
+	"google.protobuf.ListValue":   structPackage.Ident("ListValue"),
+	"google.protobuf.Any":         anyPackage.Ident("Any"),
+	"google.protobuf.DoubleValue": wrappersPackage.Ident("DoubleValue"),
+	"google.protobuf.FloatValue":  wrappersPackage.Ident("FloatValue"),
+	"google.protobuf.Int64Value":  wrappersPackage.Ident("Int64Value"),
+	"google.protobuf.UInt64Value": wrappersPackage.Ident("UInt64Value"),
+	"google.protobuf.Int32Value":  wrappersPackage.Ident("Int32Value"),
+	"google.protobuf.UInt32Value": wrappersPackage.Ident("UInt32Value"),
+	"google.protobuf.BoolValue":   wrappersPackage.Ident("BoolValue"),
+	"google.protobuf.StringValue": wrappersPackage.Ident("StringValue"),
+	"google.protobuf.BytesValue":  wrappersPackage.Ident("BytesValue"),
+}
+
+// protoGoTypes are the Go types protoc-gen-go generates for the protobuf
+// scalar kinds
+var protoGoTypes = map[protoreflect.Kind]string{
+	protoreflect.DoubleKind:   "float64",
+	protoreflect.FloatKind:    "float32",
+	protoreflect.Int32Kind:    "int32",
+	protoreflect.Sint32Kind:   "int32",
+	protoreflect.Sfixed32Kind: "int32",
+	protoreflect.Uint32Kind:   "uint32",
+	protoreflect.Fixed32Kind:  "uint32",
+	protoreflect.Int64Kind:    "int64",
+	protoreflect.Sint64Kind:   "int64",
+	protoreflect.Sfixed64Kind: "int64",
+	protoreflect.Uint64Kind:   "uint64",
+	protoreflect.Fixed64Kind:  "uint64",
+	protoreflect.BoolKind:     "bool",
+	protoreflect.StringKind:   "string",
+	protoreflect.BytesKind:    "[]byte",
+}
+
+// numericGoTypes are the Go types converted to each other with a cast
+var numericGoTypes = map[string]bool{
+	"int": true, "int32": true, "uint32": true, "int64": true, "uint64": true,
+	"float32": true, "float64": true,
+}
+
+// wellKnownConversion names the functions generated to convert a
+// well-known type to the google.protobuf.Value the JSON scalar is bound to
+// and back
+type wellKnownConversion struct {
+	FromProto string
+	ToProto   string
+}
+
+// wellKnownConversions are the well-known types conversion functions are
+// generated for, in the order they are generated. The other well-known
+// types are the models of their scalars.
+var wellKnownConversions = []protoreflect.FullName{
+	"google.protobuf.Struct",
+	"google.protobuf.ListValue",
+	"google.protobuf.Any",
+}
+
+var wellKnownConversionFuncs = map[protoreflect.FullName]wellKnownConversion{
+	"google.protobuf.Struct":    {"JSONFromStruct", "JSONToStruct"},
+	"google.protobuf.ListValue": {"JSONFromListValue", "JSONToListValue"},
+	"google.protobuf.Any":       {"JSONFromAny", "JSONToAny"},
+}
+
+// wrapperConstructors are the wrapperspb functions creating each wrapper
+var wrapperConstructors = map[protoreflect.FullName]string{
+	"google.protobuf.DoubleValue": "Double",
+	"google.protobuf.FloatValue":  "Float",
+	"google.protobuf.Int64Value":  "Int64",
+	"google.protobuf.UInt64Value": "UInt64",
+	"google.protobuf.Int32Value":  "Int32",
+	"google.protobuf.UInt32Value": "UInt32",
+	"google.protobuf.BoolValue":   "Bool",
+	"google.protobuf.StringValue": "String",
+	"google.protobuf.BytesValue":  "Bytes",
+}
+
+// registerTypes records the GraphQL types of a schema, and the objects,
+// inputs, enums and custom scalars converters are generated for. Types
+// generated by several schemas are converted once.
+func (rg *resolverGenerator) registerTypes(data *TemplateData) {
+	for _, msg := range data.Messages {
+		if _, ok := rg.types[msg.Name]; !ok {
+			rg.objects = append(rg.objects, msg)
+		}
+		rg.types[msg.Name] = objectKind
+	}
+	for _, conn := range data.Connections {
+		rg.types[conn.Name] = objectKind
+		rg.types[conn.EdgeName] = objectKind
+		rg.types["PageInfo"] = objectKind
+	}
+	for _, entry := range data.MapEntries {
+		rg.types[entry.Name] = objectKind
+		rg.types[entry.Name+"Input"] = inputKind
+	}
+	for _, oneof := range data.Oneofs {
+		rg.types[oneof.Name] = unionKind
+		rg.types[oneof.Name+"Input"] = inputKind
+		for _, member := range oneof.Members {
+			if member.Wrapped {
+				rg.types[member.TypeName] = objectKind
+			}
+		}
+	}
+	for _, input := range data.Inputs {
+		if _, ok := rg.types[input.Name]; !ok {
+			rg.inputs = append(rg.inputs, input)
+		}
+		rg.types[input.Name] = inputKind
+	}
+	for _, enum := range data.Enums {
+		if _, ok := rg.types[enum.Name]; !ok {
+			rg.enums = append(rg.enums, enum)
+		}
+		rg.types[enum.Name] = enumKind
+	}
+	for _, scalar := range data.Scalars {
+		if _, ok := rg.types[scalar.Name]; !ok {
+			rg.scalars = append(rg.scalars, scalar.Name)
+		}
+		rg.types[scalar.Name] = scalarKind
+	}
+}
+
+// generateScalarModels generates the gqlgen models of the custom scalars,
+// binding them to the Go types the conversions use, and the marshalers
+// they are bound to in the resolver package. Scalars with no model, and
+// those bound with the scalar_model option, are left to bind in gqlgen.yml.
+func (rg *resolverGenerator) generateScalarModels() error {
+	models := newModelsGenerator(resolverPluginName, string(rg.pkg))
+	for _, scalar := range rg.scalars {
+		if _, ok := rg.scalarModels[scalar]; ok {
+			continue
+		}
+		if model, ok := defaultScalarModel(scalar); ok {
+			models.bindScalar(scalar, model.GoType)
+		}
+	}
+	if len(models.scalars) == 0 {
+		return nil
+	}
+	return models.generate(rg.gen)
+}
+
+// scalarModel returns the model of the GraphQL scalar name: the one set
+// with the scalar_model option, or the one gqlgen generates its own models
+// with by default
+func (rg *resolverGenerator) scalarModel(name string) (scalarModel, bool) {
+	if model, ok := rg.scalarModels[name]; ok {
+		return model, true
+	}
+	return defaultScalarModel(name)
+}
+
+// scalarGoType returns the Go type of the model of the GraphQL scalar name
+func (rg *resolverGenerator) scalarGoType(gf *protogen.GeneratedFile, name string) (string, bool) {
+	model, ok := rg.scalarModel(name)
+	if !ok {
+		return "", false
+	}
+	if ident, ok := wellKnownGoTypes[model.GoType]; ok {
+		return "*" + gf.QualifiedGoIdent(ident), true
+	}
+	return model.GoType, true
+}
+
+// scalarModelType returns the Go type of the model of the GraphQL scalar
+// name as a scalarModel GoType, or an empty string if it has none
+func (rg *resolverGenerator) scalarModelType(name string) string {
+	model, _ := rg.scalarModel(name)
+	return model.GoType
+}
+
+// isNillable reports whether the model of the GraphQL type gqlType can be
+// nil, so that gqlgen doesn't use a pointer for it when it's nullable:
+// objects, inputs and unions, and scalars bound to slices or messages
+func (rg *resolverGenerator) isNillable(gqlType string) bool {
+	switch rg.types[gqlType] {
+	case objectKind, inputKind, unionKind:
+		return true
+	case enumKind:
+		return false
+	}
+	model, ok := rg.scalarModel(gqlType)
+	_, message := wellKnownGoTypes[model.GoType]
+	return !ok || message || strings.HasPrefix(model.GoType, "[]")
+}
+
+// goType returns the Go type gqlgen uses for f in the models and, for
+// arguments, in the resolver signatures. Objects and inputs are pointers,
+// except non-null input object arguments; nullable scalars and enums are
+// pointers unless they can be nil; lists hold non-null elements.
+func (rg *resolverGenerator) goType(gf *protogen.GeneratedFile, f *Field, arg bool) string {
+	var typ string
+	switch kind := rg.types[f.GraphQLType]; kind {
+	case objectKind, inputKind:
+		typ = "*" + gf.QualifiedGoIdent(rg.model.Ident(goName(f.GraphQLType)))
+		if kind == inputKind && arg && f.NonNull && !f.List && f.Map == nil {
+			typ = typ[1:]
+		}
+	case enumKind, unionKind:
+		typ = gf.QualifiedGoIdent(rg.model.Ident(goName(f.GraphQLType)))
+	default:
+		var ok bool
+		if typ, ok = rg.scalarGoType(gf, f.GraphQLType); !ok {
+			typ = "any"
+		}
+	}
+
+	switch {
+	case f.List || f.Map != nil:
+		return "[]" + typ
+	case !f.NonNull && !rg.isNillable(f.GraphQLType):
+		return "*" + typ
+	}
+	return typ
+}
+
+// protoGoType returns the Go type protoc-gen-go generates for a single
+// value of f
+func protoGoType(gf *protogen.GeneratedFile, f *protogen.Field) string {
+	switch {
+	case f.Message != nil:
+		return "*" + gf.QualifiedGoIdent(f.Message.GoIdent)
+	case f.Enum != nil:
+		return gf.QualifiedGoIdent(f.Enum.GoIdent)
+	}
+	return protoGoTypes[f.Desc.Kind()]
+}
+
+// isProtoPointer reports whether protoc-gen-go generates a pointer for the
+// singular scalar field f, to track its presence
+func isProtoPointer(f *protogen.Field) bool {
+	return f.Desc.HasPresence() && f.Message == nil && f.Desc.Kind() != protoreflect.BytesKind && !isRealOneof(f)
+}
+
+// isObjectMessage reports whether f holds a message converted to an object
+// or input, whose conversion functions handle nil
+func isObjectMessage(f *protogen.Field) bool {
+	return f.Message != nil && !isWellKnownType(f.Message) && !f.Desc.IsMap()
+}
+
+// wellKnownConversion returns the functions converting the well-known type
+// name to the model of the GraphQL scalar gqlType and back, recording them
+// as used so they are generated. Both are empty when the scalar is bound to
+// the well-known type itself. It returns false if the well-known type isn't
+// converted to gqlType.
+func (rg *resolverGenerator) wellKnownConversion(name protoreflect.FullName, gqlType string) (wellKnownConversion, bool) {
+	model := rg.scalarModelType(gqlType)
+	if model == string(name) {
+		return wellKnownConversion{}, true
+	}
+	conv, ok := wellKnownConversionFuncs[name]
+	if !ok || model != "google.protobuf.Value" {
+		return conv, false
+	}
+	rg.wellKnownTypes[name] = true
+	return conv, true
+}
+
+// isNilSafe reports whether the message field f is converted to the model
+// of the GraphQL type gqlType and back without checking it's set: objects,
+// and well-known types whose scalar models are messages too
+func (rg *resolverGenerator) isNilSafe(f *protogen.Field, gqlType string) bool {
+	if isObjectMessage(f) {
+		return true
+	}
+	_, message := wellKnownGoTypes[rg.scalarModelType(gqlType)]
+	return f.Message != nil && !isWrapperType(f.Message) && message
+}
+
+// fromProtoValue returns the expression converting src, a single value of
+// the proto field f, to the model value of the GraphQL type gqlType.
+// Messages converted to objects may be nil, other messages must be set. It
+// returns false if the value can't be converted.
+func (rg *resolverGenerator) fromProtoValue(f *protogen.Field, gqlType, src string) (string, bool) {
+	switch {
+	case f.Enum != nil:
+		return goName(gqlType) + "FromProto(" + src + ")", true
+	case f.Message == nil:
+		return convertScalar(src, protoGoTypes[f.Desc.Kind()], rg.scalarModelType(gqlType))
+	case isWrapperType(f.Message):
+		return convertScalar(src+".GetValue()", protoGoTypes[f.Message.Fields[0].Desc.Kind()], rg.scalarModelType(gqlType))
+	case f.Message.Desc.FullName() == "google.protobuf.Empty":
+		return src + " != nil", gqlType == "Boolean"
+	case isWellKnownType(f.Message):
+		conv, ok := rg.wellKnownConversion(f.Message.Desc.FullName(), gqlType)
+		if conv.FromProto == "" {
+			return src, ok
+		}
+		return conv.FromProto + "(" + src + ")", ok
+	}
+	return goName(gqlType) + "FromProto(" + src + ")", true
+}
+
+// toProtoValue returns the expression converting src, a single model value
+// of the GraphQL type gqlType, to a value of the proto field f. Nil inputs
+// are converted to nil messages. It returns false if the value can't be
+// converted.
+func (rg *resolverGenerator) toProtoValue(gf *protogen.GeneratedFile, f *protogen.Field, gqlType, src string) (string, bool) {
+	switch {
+	case f.Enum != nil:
+		return goName(gqlType) + "ToProto(" + src + ")", true
+	case f.Message == nil:
+		return convertScalar(src, rg.scalarModelType(gqlType), protoGoTypes[f.Desc.Kind()])
+	case isWrapperType(f.Message):
+		v, ok := convertScalar(src, rg.scalarModelType(gqlType), protoGoTypes[f.Message.Fields[0].Desc.Kind()])
+		if !ok {
+			return "", false
+		}
+		return gf.QualifiedGoIdent(wrappersPackage.Ident(wrapperConstructors[f.Message.Desc.FullName()])) + "(" + v + ")", true
+	case f.Message.Desc.FullName() == "google.protobuf.Empty":
+		if gqlType != "Boolean" {
+			return "", false
+		}
+		rg.useEmpty = true
+		return "emptyToProto(" + src + ")", true
+	case isWellKnownType(f.Message):
+		conv, ok := rg.wellKnownConversion(f.Message.Desc.FullName(), gqlType)
+		if conv.ToProto == "" {
+			return src, ok
+		}
+		return conv.ToProto + "(" + src + ")", ok
+	}
+	return goName(gqlType) + "ToProto(" + src + ")", true
+}
+
+// convertScalar returns the expression converting src from the Go type from
+// to the Go type to. Numbers are cast to each other; other types must match.
+func convertScalar(src, from, to string) (string, bool) {
+	switch {
+	case from == "" || to == "":
+		return "", false
+	case from == to:
+		return src, true
+	case numericGoTypes[from] && numericGoTypes[to]:
+		return to + "(" + src + ")", true
+	}
+	return "", false
+}
+
+// fromProtoField generates the statements setting dst, the model value of
+// f, from the proto message src. Unset fields with presence, and enums
+// whose zero value is dropped when zero, are left nil, and maps are
+// converted to entries sorted by key. It returns false if the
+// field can't be converted.
+func (rg *resolverGenerator) fromProtoField(gf *protogen.GeneratedFile, f *Field, src, dst string) bool {
+	if f.Oneof != nil {
+		return rg.fromProtoOneof(gf, f, src, dst)
+	}
+	pf := f.field
+	if pf == nil || isRealOneof(pf) {
+		return false
+	}
+
+	get := src + ".Get" + pf.GoName + "()"
+	switch {
+	case f.Map != nil:
+		return rg.fromProtoMap(gf, f, get, dst)
+	case f.List:
+		v, ok := rg.fromProtoValue(pf, f.GraphQLType, "v")
+		if !ok {
+			return false
+		}
+		gf.P("for _, v := range ", get, " {")
+		gf.P(dst, " = append(", dst, ", ", v, ")")
+		gf.P("}")
+		return true
+	case rg.isNilSafe(pf, f.GraphQLType):
+		v, ok := rg.fromProtoValue(pf, f.GraphQLType, get)
+		if !ok {
+			return false
+		}
+		gf.P(dst, " = ", v)
+		return true
+	}
+
+	value := get
+	if pf.Message != nil {
+		value = src + "." + pf.GoName
+	}
+	v, ok := rg.fromProtoValue(pf, f.GraphQLType, value)
+	if !ok {
+		return false
+	}
+	if !f.NonNull && !rg.isNillable(f.GraphQLType) {
+		v = "ptr(" + v + ")"
+	}
+	var set []string
+	if pf.Desc.HasPresence() && !(f.NonNull && pf.Message == nil) {
+		set = append(set, src+"."+pf.GoName+" != nil")
+	}
+	if pf.Enum != nil && !f.NonNull && zeroIsNull(rg.enums, f.GraphQLType) {
+		set = append(set, get+" != 0")
+	}
+	if len(set) == 0 {
+		gf.P(dst, " = ", v)
+		return true
+	}
+	gf.P("if ", strings.Join(set, " && "), " {")
+	gf.P(dst, " = ", v)
+	gf.P("}")
+	return true
+}
+
+// fromProtoMap generates the statements converting the proto map src of f
+// to the list of key/value entries dst, sorted by key
+func (rg *resolverGenerator) fromProtoMap(gf *protogen.GeneratedFile, f *Field, src, dst string) bool {
+	key, value := f.field.Message.Fields[0], f.field.Message.Fields[1]
+	k, ok := rg.fromProtoValue(key, f.Map.KeyType, "k")
+	if !ok {
+		return false
+	}
+	v, ok := rg.fromProtoValue(value, f.Map.ValueType, "v")
+	if !ok {
+		return false
+	}
+
+	// Bool keys aren't ordered, and there are only two of them
+	if key.Desc.Kind() == protoreflect.BoolKind {
+		gf.P("for k, v := range ", src, " {")
+	} else {
+		gf.P("for _, k := range ", slicesPackage.Ident("Sorted"), "(", mapsPackage.Ident("Keys"), "(", src, ")) {")
+		gf.P("v := ", src, "[k]")
+	}
+	gf.P(dst, " = append(", dst, ", &", rg.model.Ident(goName(f.Map.Name)), "{Key: ", k, ", Value: ", v, "})")
+	gf.P("}")
+	return true
+}
+
+// fromProtoOneof generates the statements setting dst, the union of the
+// oneof field f, to the member set on the proto message src. Scalars and
+// other members that aren't objects of their own are wrapped in their
+// member objects.
+func (rg *resolverGenerator) fromProtoOneof(gf *protogen.GeneratedFile, f *Field, src, dst string) bool {
+	oneof := f.Oneof.Members[0].Field.field.Oneof
+	gf.P("switch v := ", src, ".Get", oneof.GoName, "().(type) {")
+	for _, member := range f.Oneof.Members {
+		mf := member.Field.field
+		v, ok := rg.fromProtoValue(mf, member.Field.GraphQLType, "v."+mf.GoName)
+		if !ok {
+			log.Printf("Warning: %s can't be converted to %s", mf.Desc.FullName(), member.Field.TypeRef())
+			continue
+		}
+		if member.Wrapped {
+			v = "&" + gf.QualifiedGoIdent(rg.model.Ident(goName(member.TypeName))) + "{" + goName(member.Field.Name) + ": " + v + "}"
+		}
+
+		gf.P("case *", mf.GoIdent, ":")
+		// A nil object would be a non-nil union
+		if mf.Message != nil {
+			gf.P("if v.", mf.GoName, " != nil {")
+			gf.P(dst, " = ", v)
+			gf.P("}")
+			continue
+		}
+		gf.P(dst, " = ", v)
+	}
+	gf.P("}")
+	return true
+}
+
+// toProtoField generates the statements setting f on the proto message dst
+// from src, its model value. Null values leave the field unset. It returns
+// false if the field can't be converted.
+func (rg *resolverGenerator) toProtoField(gf *protogen.GeneratedFile, f *Field, src, dst string) bool {
+	if f.Oneof != nil {
+		return rg.toProtoOneof(gf, f, src, dst)
+	}
+	pf := f.field
+	if pf == nil || isRealOneof(pf) {
+		return false
+	}
+
+	set := dst + "." + pf.GoName
+	switch {
+	case f.Map != nil:
+		return rg.toProtoMap(gf, f, src, set)
+	case f.List:
+		v, ok := rg.toProtoValue(gf, pf, f.GraphQLType, "v")
+		if !ok {
+			return false
+		}
+		gf.P("for _, v := range ", src, " {")
+		gf.P(set, " = append(", set, ", ", v, ")")
+		gf.P("}")
+		return true
+	case rg.isNilSafe(pf, f.GraphQLType):
+		v, ok := rg.toProtoValue(gf, pf, f.GraphQLType, src)
+		if !ok {
+			return false
+		}
+		gf.P(set, " = ", v)
+		return true
+	}
+
+	value := src
+	if !f.NonNull && !rg.isNillable(f.GraphQLType) {
+		value = "*" + src
+	}
+	v, ok := rg.toProtoValue(gf, pf, f.GraphQLType, value)
+	if !ok {
+		return false
+	}
+	if isProtoPointer(pf) {
+		v = "ptr(" + v + ")"
+	}
+	if f.NonNull {
+		gf.P(set, " = ", v)
+		return true
+	}
+	gf.P("if ", src, " != nil {")
+	gf.P(set, " = ", v)
+	gf.P("}")
+	return true
+}
+
+// toProtoMap generates the statements converting src, a list of key/value
+// entries, to the proto map set of f
+func (rg *resolverGenerator) toProtoMap(gf *protogen.GeneratedFile, f *Field, src, set string) bool {
+	key, value := f.field.Message.Fields[0], f.field.Message.Fields[1]
+	k, ok := rg.toProtoValue(gf, key, f.Map.KeyType, "e.Key")
+	if !ok {
+		return false
+	}
+	v, ok := rg.toProtoValue(gf, value, f.Map.InputValueType, "e.Value")
+	if !ok {
+		return false
+	}
+
+	gf.P("for _, e := range ", src, " {")
+	gf.P("if ", set, " == nil {")
+	gf.P(set, " = make(map[", protoGoType(gf, key), "]", protoGoType(gf, value), ", len(", src, "))")
+	gf.P("}")
+	gf.P(set, "[", k, "] = ", v)
+	gf.P("}")
+	return true
+}
+
+// toProtoOneof generates the statements setting the oneof of f on the proto
+// message dst to the member set on src, its @oneOf input object
+func (rg *resolverGenerator) toProtoOneof(gf *protogen.GeneratedFile, f *Field, src, dst string) bool {
+	oneof := f.Oneof.Members[0].Field.field.Oneof
+	gf.P("switch {")
+	gf.P("case ", src, " == nil:")
+	for _, member := range f.Oneof.Members {
+		mf := member.Field.field
+		in := src + "." + goName(member.InputField.Name)
+
+		value := in
+		if !rg.isNillable(member.InputField.GraphQLType) {
+			value = "*" + in
+		}
+		v, ok := rg.toProtoValue(gf, mf, member.InputField.GraphQLType, value)
+		if !ok {
+			log.Printf("Warning: %s can't be converted to %s", member.InputField.TypeRef(), mf.Desc.FullName())
+			continue
+		}
+		gf.P("case ", in, " != nil:")
+		gf.P(dst, ".", oneof.GoName, " = &", mf.GoIdent, "{", mf.GoName, ": ", v, "}")
+	}
+	gf.P("}")
+	return true
+}
+
+// generateConversions generates convert.go with the functions converting
+// the proto messages and enums to the gqlgen models of their object types
+// and enums (ProductFromProto), the models of input objects and enums back
+// to proto (ProductInputToProto) and the well-known types to the Go types of
+// their scalars and back (JSONFromStruct)
+func (rg *resolverGenerator) generateConversions() {
+	gf := rg.newFile("convert.go")
+	gf.P("// ptr returns a pointer to v")
+	gf.P("func ptr[T any](v T) *T {")
+	gf.P("return &v")
+	gf.P("}")
+
+	for _, msg := range rg.objects {
+		if msg.message == nil {
+			continue
+		}
+		model := rg.model.Ident(goName(msg.Name))
+		gf.P()
+		gf.P("// ", goName(msg.Name), "FromProto converts a ", msg.message.Desc.FullName(), " to its GraphQL object")
+		gf.P("func ", goName(msg.Name), "FromProto(in *", msg.message.GoIdent, ") *", model, " {")
+		gf.P("if in == nil {")
+		gf.P("return nil")
+		gf.P("}")
+		gf.P("out := &", model, "{}")
+		for _, f := range msg.Fields {
+			if !rg.fromProtoField(gf, f, "in", "out."+goName(f.Name)) {
+				log.Printf("Warning: %s.%s can't be converted to %s", msg.message.Desc.FullName(), f.ProtoName, f.TypeRef())
+			}
+		}
+		gf.P("return out")
+		gf.P("}")
+	}
+
+	for _, input := range rg.inputs {
+		gf.P()
+		gf.P("// ", goName(input.Name), "ToProto converts the input object ", input.Name, " to a ", input.message.Desc.FullName())
+		gf.P("func ", goName(input.Name), "ToProto(in *", rg.model.Ident(goName(input.Name)), ") *", input.message.GoIdent, " {")
+		gf.P("if in == nil {")
+		gf.P("return nil")
+		gf.P("}")
+		gf.P("out := &", input.message.GoIdent, "{}")
+		for _, f := range input.Fields {
+			if !rg.toProtoField(gf, f, "in."+goName(f.Name), "out") {
+				log.Printf("Warning: %s can't be converted to %s.%s", f.TypeRef(), input.message.Desc.FullName(), f.ProtoName)
+			}
+		}
+		gf.P("return out")
+		gf.P("}")
+	}
+
+	for _, enum := range rg.enums {
+		rg.generateEnumConversions(gf, enum)
+	}
+	rg.generateWellKnownConversions(gf)
+}
+
+// generateEnumConversions generates the functions converting the values of
+// a proto enum to its GraphQL enum and back. Values left out of the GraphQL
+// enum convert to the empty value, and unknown GraphQL values to zero.
+func (rg *resolverGenerator) generateEnumConversions(gf *protogen.GeneratedFile, enum *Enum) {
+	name := goName(enum.Name)
+	model := rg.model.Ident(name)
+	gf.P()
+	gf.P("// ", name, "FromProto converts a ", enum.enum.Desc.FullName(), " to its GraphQL enum value")
+	gf.P("func ", name, "FromProto(in ", enum.enum.GoIdent, ") ", model, " {")
+	gf.P("switch in {")
+	numbers := make(map[protoreflect.EnumNumber]bool)
+	for _, v := range enum.Values {
+		// Aliases share the case of the first value with their number
+		if numbers[v.value.Desc.Number()] {
+			continue
+		}
+		numbers[v.value.Desc.Number()] = true
+		gf.P("case ", v.value.GoIdent, ":")
+		gf.P("return ", rg.model.Ident(goName(enum.Name+"_"+v.Name)))
+	}
+	gf.P("}")
+	gf.P(`return ""`)
+	gf.P("}")
+	gf.P()
+	gf.P("// ", name, "ToProto converts a GraphQL enum value to a ", enum.enum.Desc.FullName())
+	gf.P("func ", name, "ToProto(in ", model, ") ", enum.enum.GoIdent, " {")
+	gf.P("switch in {")
+	for _, v := range enum.Values {
+		gf.P("case ", rg.model.Ident(goName(enum.Name+"_"+v.Name)), ":")
+		gf.P("return ", v.value.GoIdent)
+	}
+	gf.P("}")
+	gf.P("return 0")
+	gf.P("}")
+}
+
+// generateWellKnownConversions generates the conversion functions of the
+// well-known types used by the other conversions. JSON values that aren't
+// valid for their well-known type convert to nil.
+func (rg *resolverGenerator) generateWellKnownConversions(gf *protogen.GeneratedFile) {
+	value := wellKnownGoTypes["google.protobuf.Value"]
+	json := false
+	for _, name := range wellKnownConversions {
+		if !rg.wellKnownTypes[name] {
+			continue
+		}
+		conv := wellKnownConversionFuncs[name]
+		message := wellKnownGoTypes[string(name)]
+
+		gf.P()
+		gf.P("// ", conv.FromProto, " converts a ", name, " to a JSON scalar")
+		gf.P("func ", conv.FromProto, "(in *", message, ") *", value, " {")
+		gf.P("if in == nil {")
+		gf.P("return nil")
+		gf.P("}")
+		switch name {
+		case "google.protobuf.Struct":
+			gf.P("return ", structPackage.Ident("NewStructValue"), "(in)")
+		case "google.protobuf.ListValue":
+			gf.P("return ", structPackage.Ident("NewListValue"), "(in)")
+		case "google.protobuf.Any":
+			json = true
+			gf.P("out := &", value, "{}")
+			gf.P("if !convertProtoJSON(in, out) {")
+			gf.P("return nil")
+			gf.P("}")
+			gf.P("return out")
+		}
+		gf.P("}")
+
+		gf.P()
+		gf.P("// ", conv.ToProto, " converts a JSON scalar to a ", name)
+		gf.P("func ", conv.ToProto, "(in *", value, ") *", message, " {")
+		switch name {
+		case "google.protobuf.Struct":
+			gf.P("return in.GetStructValue()")
+		case "google.protobuf.ListValue":
+			gf.P("return in.GetListValue()")
+		case "google.protobuf.Any":
+			gf.P("if in == nil {")
+			gf.P("return nil")
+			gf.P("}")
+			gf.P("out := &", message, "{}")
+			gf.P("if !convertProtoJSON(in, out) {")
+			gf.P("return nil")
+			gf.P("}")
+			gf.P("return out")
+		}
+		gf.P("}")
+	}
+
+	if json {
+		gf.P()
+		gf.P("// convertProtoJSON sets out from the JSON encoding of in, reporting")
+		gf.P("// whether it is a valid JSON value for out")
+		gf.P("func convertProtoJSON(in, out ", protoPackage.Ident("Message"), ") bool {")
+		gf.P("b, err := ", protojsonPackage.Ident("Marshal"), "(in)")
+		gf.P("if err != nil {")
+		gf.P("return false")
+		gf.P("}")
+		gf.P("return ", protojsonPackage.Ident("Unmarshal"), "(b, out) == nil")
+		gf.P("}")
+	}
+
+	if rg.useEmpty {
+		gf.P()
+		gf.P("// emptyToProto converts true to a google.protobuf.Empty and false to nil")
+		gf.P("func emptyToProto(in bool) *", emptyPackage.Ident("Empty"), " {")
+		gf.P("if !in {")
+		gf.P("return nil")
+		gf.P("}")
+		gf.P("return &", emptyPackage.Ident("Empty"), "{}")
+		gf.P("}")
+	}
+}
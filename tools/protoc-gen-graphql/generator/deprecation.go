@@ -0,0 +1,115 @@
+package generator
+
+import (
+	"encoding/json"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// deprecationPrefix starts the comment line giving a deprecation reason
+const deprecationPrefix = "Deprecated:"
+
+// deprecation reports whether a proto element is deprecated and why. It is
+// deprecated if it sets the deprecated option or a deprecation reason
+// option; without a reason option, the reason is taken from a comment line
+// starting with "Deprecated:", if there is one.
+func deprecation(deprecated bool, reason string, comments protogen.CommentSet) (bool, string) {
+	if reason != "" {
+		return true, reason
+	}
+	if !deprecated {
+		return false, ""
+	}
+	for _, c := range []protogen.Comments{comments.Trailing, comments.Leading} {
+		lines := strings.Split(string(c), "\n")
+		for i := len(lines) - 1; i >= 0; i-- {
+			if line := strings.TrimSpace(lines[i]); strings.HasPrefix(line, deprecationPrefix) {
+				return true, strings.TrimSpace(strings.TrimPrefix(line, deprecationPrefix))
+			}
+		}
+	}
+	return true, ""
+}
+
+// deprecatedDescription returns the description of an element rendered
+// with @deprecated if deprecated is set. The comment lines starting with
+// "Deprecated:" are left out of it then, as @deprecated gives the reason.
+func deprecatedDescription(comments protogen.CommentSet, deprecated bool) string {
+	if !deprecated {
+		return description(comments)
+	}
+	strip := func(c protogen.Comments) protogen.Comments {
+		var lines []string
+		for _, line := range strings.Split(string(c), "\n") {
+			if !strings.HasPrefix(strings.TrimSpace(line), deprecationPrefix) {
+				lines = append(lines, line)
+			}
+		}
+		return protogen.Comments(strings.Join(lines, "\n"))
+	}
+
+	stripped := protogen.CommentSet{
+		Leading:  strip(comments.Leading),
+		Trailing: strip(comments.Trailing),
+	}
+	for _, c := range comments.LeadingDetached {
+		stripped.LeadingDetached = append(stripped.LeadingDetached, strip(c))
+	}
+	return description(stripped)
+}
+
+// fieldDeprecation reports whether f is deprecated and why. Fields of a
+// deprecated message type are deprecated along with the type.
+func fieldDeprecation(f *protogen.Field) (bool, string) {
+	opts, _ := f.Desc.Options().(*descriptorpb.FieldOptions)
+	if deprecated, reason := deprecation(opts.GetDeprecated(), fieldAnnotations(f).DeprecationReason, f.Comments); deprecated {
+		return deprecated, reason
+	}
+	return messageDeprecation(f.Message)
+}
+
+// messageDeprecation reports whether msg is deprecated and why
+func messageDeprecation(msg *protogen.Message) (bool, string) {
+	if msg == nil {
+		return false, ""
+	}
+	opts, _ := msg.Desc.Options().(*descriptorpb.MessageOptions)
+	return deprecation(opts.GetDeprecated(), messageAnnotations(msg).DeprecationReason, msg.Comments)
+}
+
+// methodDeprecation reports whether method is deprecated and why
+func methodDeprecation(method *protogen.Method) (bool, string) {
+	opts, _ := method.Desc.Options().(*descriptorpb.MethodOptions)
+	return deprecation(opts.GetDeprecated(), methodAnnotations(method).DeprecationReason, method.Comments)
+}
+
+// enumValueDeprecation reports whether v is deprecated and why
+func enumValueDeprecation(v *protogen.EnumValue) (bool, string) {
+	opts, _ := v.Desc.Options().(*descriptorpb.EnumValueOptions)
+	return deprecation(opts.GetDeprecated(), enumValueAnnotations(v).DeprecationReason, v.Comments)
+}
+
+// DeprecatedDirective returns the @deprecated directive of a deprecated
+// argument or input field, or "" if it isn't deprecated
+func (f *Field) DeprecatedDirective() string {
+	switch {
+	case !f.Deprecated:
+		return ""
+	case f.DeprecationReason == "":
+		return " @deprecated"
+	}
+	return " @deprecated(reason: " + quote(f.DeprecationReason) + ")"
+}
+
+// quote returns s as a GraphQL string literal
+func quote(s string) string {
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(s); err != nil {
+		return `""`
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,47 @@
+package generator
+
+import "sort"
+
+// Directive is a custom directive declared in the generated schema. The
+// federation directives are provided by the gateway and aren't declared.
+type Directive struct {
+	Name       string
+	Definition string
+	Comment    string
+}
+
+// customDirectives are the non-federation directives the generator can emit
+var customDirectives = map[string]*Directive{
+	"computed": {
+		Name:       "computed",
+		Definition: "directive @computed(fields: String!) on FIELD_DEFINITION",
+		Comment:    "The field is computed from the given fields of other services.",
+	},
+	"constraint": {
+		Name: "constraint",
+		Definition: "directive @constraint(minLength: Int, maxLength: Int, startsWith: String, endsWith: String, " +
+			"contains: String, notContains: String, pattern: String, format: String, " +
+			"min: Float, max: Float, exclusiveMin: Float, exclusiveMax: Float, minItems: Int, maxItems: Int) " +
+			"on INPUT_FIELD_DEFINITION | ARGUMENT_DEFINITION",
+		Comment: "The value must satisfy the validation rules of the proto field.",
+	},
+}
+
+// useDirective records that the custom directive name is used by the schema
+func (tm *typeMapper) useDirective(name string) {
+	if _, ok := customDirectives[name]; ok {
+		tm.directives[name] = true
+	}
+}
+
+// Directives returns the custom directives used so far, sorted by name
+func (tm *typeMapper) Directives() []*Directive {
+	var directives []*Directive
+	for name := range tm.directives {
+		directives = append(directives, customDirectives[name])
+	}
+	sort.Slice(directives, func(i, j int) bool {
+		return directives[i].Name < directives[j].Name
+	})
+	return directives
+}
@@ -0,0 +1,118 @@
+package generator
+
+import (
+	"strings"
+	"unicode"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// Enum is a GraphQL enum generated from a proto enum
+type Enum struct {
+	Name    string
+	Values  []*EnumValue
+	Comment string
+
+	enum *protogen.Enum
+}
+
+// EnumValue is a single value of a GraphQL enum
+type EnumValue struct {
+	Name              string
+	Comment           string
+	Deprecated        bool
+	DeprecationReason string
+
+	value *protogen.EnumValue
+}
+
+// extractEnum converts a proto enum to a GraphQL enum named name. The enum's name is
+// stripped from the front of its values (STATUS_ACTIVE becomes ACTIVE) and,
+// if dropUnspecified is set, the STATUS_UNSPECIFIED style zero value is
+// left out.
+func extractEnum(e *protogen.Enum, name string, dropUnspecified bool) *Enum {
+	prefix := screamingSnakeCase(string(e.Desc.Name())) + "_"
+
+	// Only strip the prefix if every value has it and the result is still a
+	// valid and unique GraphQL name
+	strip := true
+	seen := make(map[string]bool)
+	for _, v := range e.Values {
+		name := string(v.Desc.Name())
+		trimmed := strings.TrimPrefix(name, prefix)
+		if trimmed == name || trimmed == "" || unicode.IsDigit(rune(trimmed[0])) || seen[trimmed] {
+			strip = false
+			break
+		}
+		seen[trimmed] = true
+	}
+
+	enum := &Enum{
+		Name:    name,
+		Comment: description(e.Comments),
+		enum:    e,
+	}
+	for _, v := range e.Values {
+		name := string(v.Desc.Name())
+		if dropUnspecified && isUnspecifiedValue(v) {
+			continue
+		}
+		if strip {
+			name = strings.TrimPrefix(name, prefix)
+		}
+
+		deprecated, reason := enumValueDeprecation(v)
+		enum.Values = append(enum.Values, &EnumValue{
+			Name:              name,
+			Comment:           deprecatedDescription(v.Comments, deprecated),
+			Deprecated:        deprecated,
+			DeprecationReason: reason,
+			value:             v,
+		})
+	}
+	return enum
+}
+
+// isUnspecifiedValue reports whether v is a FOO_UNSPECIFIED zero value,
+// which enum_drop_unspecified leaves out of the GraphQL enum
+func isUnspecifiedValue(v *protogen.EnumValue) bool {
+	return len(v.Parent.Values) > 1 && v.Desc.Number() == 0 && strings.HasSuffix(string(v.Desc.Name()), "_UNSPECIFIED")
+}
+
+// dropsZeroValue reports whether every zero value of e is left out of its
+// GraphQL enum with enum_drop_unspecified, so that fields of the enum have no
+// GraphQL value when unset and are nullable
+func dropsZeroValue(e *protogen.Enum, dropUnspecified bool) bool {
+	if !dropUnspecified {
+		return false
+	}
+	for _, v := range e.Values {
+		if v.Desc.Number() == 0 && !isUnspecifiedValue(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasZeroValue reports whether the GraphQL enum has a value for the zero
+// value of its proto enum, which unset fields of the enum hold
+func (e *Enum) hasZeroValue() bool {
+	for _, v := range e.Values {
+		if v.value.Desc.Number() == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// zeroIsNull reports whether the GraphQL enum name, one of enums, has no
+// value for the zero value of its proto enum, so that fields of the enum are
+// null when zero
+func zeroIsNull(enums []*Enum, name string) bool {
+	for _, enum := range enums {
+		if enum.Name == name {
+			return !enum.hasZeroValue()
+		}
+	}
+	return false
+}
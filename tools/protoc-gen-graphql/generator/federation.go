@@ -0,0 +1,70 @@
+package generator
+
+import "fmt"
+
+// federationSpec is the URL of the Apollo Federation spec, without version
+const federationSpec = "https://specs.apollo.dev/federation/"
+
+// federationDirectives are the Federation 2 directives the generator can
+// emit, in the order they are imported
+var federationDirectives = []string{
+	"key",
+	"external",
+	"requires",
+	"provides",
+	"shareable",
+	"inaccessible",
+	"override",
+	"tag",
+	"interfaceObject",
+}
+
+// federationDirectiveVersions are the minor versions of the v2 spec that
+// introduced the directives not available since v2.0
+var federationDirectiveVersions = map[string]int{
+	"interfaceObject": 3,
+}
+
+// FederationLink is the @link to the federation spec extending the schema
+type FederationLink struct {
+	URL     string
+	Imports []string
+}
+
+// federationLink returns the @link importing the federation directives used
+// by the rendered messages and connections, linking to the oldest spec
+// version providing all of them. Connection types are @shareable as every
+// subgraph with paginated methods declares PageInfo.
+func federationLink(messages []*Message, connections bool) *FederationLink {
+	used := map[string]bool{"shareable": connections}
+	for _, msg := range messages {
+		used["key"] = used["key"] || len(msg.Keys) > 0
+		used["shareable"] = used["shareable"] || msg.Shareable
+		used["inaccessible"] = used["inaccessible"] || msg.Inaccessible
+		used["tag"] = used["tag"] || len(msg.Tags) > 0
+		used["interfaceObject"] = used["interfaceObject"] || msg.InterfaceObject
+		for _, f := range msg.Fields {
+			used["external"] = used["external"] || f.External
+			used["requires"] = used["requires"] || f.Requires != ""
+			used["provides"] = used["provides"] || f.Provides != ""
+			used["shareable"] = used["shareable"] || f.Shareable
+			used["inaccessible"] = used["inaccessible"] || f.Inaccessible
+			used["override"] = used["override"] || f.OverrideFrom != ""
+			used["tag"] = used["tag"] || len(f.Tags) > 0
+		}
+	}
+
+	version := 0
+	link := &FederationLink{}
+	for _, name := range federationDirectives {
+		if !used[name] {
+			continue
+		}
+		link.Imports = append(link.Imports, "@"+name)
+		if v, ok := federationDirectiveVersions[name]; ok && v > version {
+			version = v
+		}
+	}
+	link.URL = fmt.Sprintf("%sv2.%d", federationSpec, version)
+	return link
+}
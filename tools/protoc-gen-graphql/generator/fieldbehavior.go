@@ -0,0 +1,95 @@
+package generator
+
+import (
+	"strings"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// typeUse is where a field's GraphQL type is used, which decides its
+// nullability, the fields it leaves out and the variant of generated types
+// it refers to
+type typeUse int
+
+const (
+	// A field of an output object
+	outputUse typeUse = iota
+	// A field of an input object or argument
+	inputUse
+	// A field of the input of an update method, which can't set IMMUTABLE
+	// fields
+	updateInputUse
+)
+
+// hasFieldBehavior reports whether f is annotated with the
+// google.api.field_behavior b
+func hasFieldBehavior(f *protogen.Field, b annotations.FieldBehavior) bool {
+	for _, behavior := range getExtension(f.Desc.Options(), annotations.E_FieldBehavior).([]annotations.FieldBehavior) {
+		if behavior == b {
+			return true
+		}
+	}
+	return false
+}
+
+// isNonNull reports whether f is non-null where it is used. Proto2 required
+// fields always are. In outputs, fields with presence (messages, proto3
+// optional and proto2 optional fields) can be unset and are nullable, as are
+// singular enum fields when the enum's zero value is dropped, as unset
+// fields have no GraphQL value; lists and maps are empty rather than null.
+// In inputs and arguments, only fields annotated REQUIRED are non-null, so
+// clients can leave out the others.
+func isNonNull(f *protogen.Field, use typeUse, dropUnspecified bool) bool {
+	if f.Desc.Cardinality() == protoreflect.Required {
+		return true
+	}
+	if use != outputUse {
+		return hasFieldBehavior(f, annotations.FieldBehavior_REQUIRED)
+	}
+	zeroDropped := f.Enum != nil && !f.Desc.IsList() && dropsZeroValue(f.Enum, dropUnspecified)
+	return !f.Desc.HasPresence() && !zeroDropped
+}
+
+// isOmitted reports whether f is left out where it is used: OUTPUT_ONLY
+// fields from inputs, INPUT_ONLY fields from outputs and IMMUTABLE fields
+// from update inputs
+func isOmitted(f *protogen.Field, use typeUse) bool {
+	switch use {
+	case outputUse:
+		return hasFieldBehavior(f, annotations.FieldBehavior_INPUT_ONLY)
+	case updateInputUse:
+		if hasFieldBehavior(f, annotations.FieldBehavior_IMMUTABLE) {
+			return true
+		}
+	}
+	return hasFieldBehavior(f, annotations.FieldBehavior_OUTPUT_ONLY)
+}
+
+// isUpdateMethod reports whether method updates a resource, following the
+// AIP-134 Update method naming
+func isUpdateMethod(method *protogen.Method) bool {
+	return strings.HasPrefix(string(method.Desc.Name()), "Update")
+}
+
+// hasImmutableFields reports whether msg, or a message its fields refer to,
+// has IMMUTABLE fields, so that its update input differs from its input
+func hasImmutableFields(msg *protogen.Message, seen map[protoreflect.FullName]bool) bool {
+	if seen[msg.Desc.FullName()] {
+		return false
+	}
+	seen[msg.Desc.FullName()] = true
+	for _, f := range msg.Fields {
+		if isRealOneof(f) || hasFieldBehavior(f, annotations.FieldBehavior_OUTPUT_ONLY) {
+			continue
+		}
+		if hasFieldBehavior(f, annotations.FieldBehavior_IMMUTABLE) {
+			return true
+		}
+		if f.Message != nil && !f.Desc.IsMap() && !isWellKnownType(f.Message) && hasImmutableFields(f.Message, seen) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,797 @@
+package generator
+
+import (
+	"embed"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	metadatav1 "github.com/fraser-isbester/federated-gql/gen/go/metadata/v1"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// Generator handles the generation of GraphQL schema files from proto definitions
+type Generator struct {
+	opts     Options
+	template *template.Template
+	scalars  map[protoreflect.Kind]string
+	// The gqlgen models scalars are bound to instead of their defaults
+	scalarModels map[string]scalarModel
+}
+
+// NewGenerator creates a new Generator instance with the provided options
+func NewGenerator(opts Options) (*Generator, error) {
+	g := &Generator{opts: opts}
+	var err error
+	if g.template, err = loadTemplate(opts.TemplatePath); err != nil {
+		return nil, fmt.Errorf("failed to load template: %v", err)
+	}
+	switch opts.InputStyle {
+	case "", InputStyleFlatten, InputStyleObject:
+	default:
+		return nil, fmt.Errorf("invalid input_style %q, expected %s or %s", opts.InputStyle, InputStyleFlatten, InputStyleObject)
+	}
+	if err := validateNaming(opts); err != nil {
+		return nil, err
+	}
+	switch opts.TypeCollisions {
+	case "", TypeCollisionsPrefix, TypeCollisionsError:
+	default:
+		return nil, fmt.Errorf("invalid type_collisions %q, expected %s or %s", opts.TypeCollisions, TypeCollisionsPrefix, TypeCollisionsError)
+	}
+	switch opts.OutputMode {
+	case "", OutputModePerService, OutputModePerFile, OutputModePerPackage, OutputModeMerged:
+	default:
+		return nil, fmt.Errorf("invalid output_mode %q, expected %s, %s, %s or %s", opts.OutputMode, OutputModePerService, OutputModePerFile, OutputModePerPackage, OutputModeMerged)
+	}
+	g.scalars = make(map[protoreflect.Kind]string)
+	for _, s := range opts.Scalars {
+		if err := parseScalarOverride(g.scalars, s); err != nil {
+			return nil, err
+		}
+	}
+	g.scalarModels = make(map[string]scalarModel)
+	for _, s := range opts.ScalarModels {
+		if err := parseScalarModel(g.scalarModels, s); err != nil {
+			return nil, err
+		}
+	}
+	return g, nil
+}
+
+var funcMap = template.FuncMap{
+	"trim":  strings.TrimSpace,
+	"quote": quote,
+}
+
+var (
+	//go:embed templates/graphql-service-schema.tmpl
+	templatesFS         embed.FS
+	defaultTemplatePath = "templates/graphql-service-schema.tmpl"
+)
+
+// Generate processes protobuf files and generates the corresponding GraphQL schema
+func (g *Generator) Generate(gen *protogen.Plugin) error {
+	gen.SupportedFeatures = uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL)
+	var models *modelsGenerator
+	if g.opts.GqlgenModels {
+		models = newModelsGenerator(schemaPluginName, g.opts.GqlgenPackage)
+	}
+	groups, err := g.schemaGroups(gen)
+	if err != nil {
+		return err
+	}
+	for _, group := range groups {
+		data, err := g.generateSchema(group, gen)
+		if err != nil {
+			return err
+		}
+		if models != nil {
+			models.registerTypes(data)
+		}
+	}
+	if models == nil {
+		return nil
+	}
+	return models.generate(gen)
+}
+
+// loadTemplate loads either the custom template specified in TemplatePath
+// or falls back to the embedded template if not found or specified
+func loadTemplate(templatePath string) (*template.Template, error) {
+	if templatePath != "" {
+		content, err := os.ReadFile(templatePath)
+		if err != nil {
+			log.Printf("Could not read template from %s: %v, falling back to embedded template", templatePath, err)
+		} else {
+			t, err := template.New(filepath.Base(templatePath)).Funcs(funcMap).Parse(string(content))
+			if err != nil {
+				log.Printf("Failed to parse template from file %s: %v, falling back to embedded template", templatePath, err)
+			} else {
+				log.Printf("Using custom template from path: %s", templatePath)
+				return t, nil
+			}
+		}
+	}
+
+	// Fallback to embedded template
+	content, err := templatesFS.ReadFile(defaultTemplatePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embedded template: %v", err)
+	}
+
+	t, err := template.New(filepath.Base(defaultTemplatePath)).Funcs(funcMap).Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse embedded template: %v", err)
+	}
+
+	log.Println("Using embedded template")
+	return t, nil
+}
+
+// TemplateData contains all data needed to render the GraphQL schema template
+type TemplateData struct {
+	// Whether the schema is a federated subgraph, rather than a plain schema
+	Federated bool
+	// The name of the subgraph the schema is generated for, empty for merged
+	// schemas
+	Subgraph string
+	// The federation spec the schema links to and the directives it imports,
+	// if the schema is federated
+	Federation *FederationLink
+	// All services defined in the proto files
+	Services []*ServiceData
+	// Whether the schema contains any mutation services
+	MutationServices bool
+	// Whether the schema contains any subscription services
+	SubscriptionServices bool
+	// All messages defined in the proto files
+	Messages []*Message
+	// Custom scalars referenced by the schema
+	Scalars []*Scalar
+	// Custom directives used by the schema
+	Directives []*Directive
+	// Enums referenced by the schema
+	Enums []*Enum
+	// Key/value entry types for map fields referenced by the schema
+	MapEntries []*MapEntry
+	// Unions and @oneOf input objects for oneofs referenced by the schema
+	Oneofs []*Oneof
+	// Input objects for request messages and the messages they reference
+	Inputs []*InputType
+	// Relay connections returned by paginated methods
+	Connections []*Connection
+	// The source file that the schema was generated from
+	Source string
+}
+
+type ServiceData struct {
+	Name      string
+	Federated bool
+	Methods   []*Method
+	Messages  []*Message
+}
+
+type Message struct {
+	Name            string
+	Fields          []*Field
+	Entity          bool
+	Keys            []*Key
+	Shareable       bool
+	Inaccessible    bool
+	Tags            []string
+	InterfaceObject bool
+	// The methods resolving the entity by its key, named by the message's
+	// (metadata.v1.provides) option
+	ReferenceMethods []*Method
+	Comment          string
+
+	message *protogen.Message
+}
+
+type Field struct {
+	Name         string
+	ProtoName    string
+	GraphQLType  string
+	NonNull      bool
+	List         bool
+	Map          *MapEntry
+	Oneof        *Oneof
+	External     bool
+	Key          bool
+	Requires     string
+	Provides     string
+	ComputedFrom string
+	Shareable    bool
+	Inaccessible bool
+	OverrideFrom string
+	Tags         []string
+	Comment      string
+	// Validation rules of input fields
+	Constraints []*Constraint
+
+	Deprecated        bool
+	DeprecationReason string
+
+	// The proto field, unset for oneofs and synthetic arguments
+	field *protogen.Field
+	// The input object of a single input argument
+	input *InputType
+}
+
+// TypeRef returns the full GraphQL type of the field, including list and
+// non-null markers (e.g. [String!]!)
+func (f *Field) TypeRef() string {
+	typeRef := f.GraphQLType
+	if f.List || f.Map != nil {
+		typeRef = "[" + typeRef + "!]"
+	}
+	if f.NonNull {
+		typeRef += "!"
+	}
+	return typeRef
+}
+
+// MapEntry is the generated key/value type for a proto map field
+type MapEntry struct {
+	Name           string
+	KeyType        string
+	ValueType      string
+	InputValueType string
+	// Whether the entry is used by output types and input arguments
+	Output bool
+	Input  bool
+}
+
+type Method struct {
+	Name       string
+	ProtoName  string
+	Type       string
+	InputArgs  string
+	OutputType string
+	Comment    string
+	// Set when the response message is unwrapped into its payload: the proto
+	// field names leading from the response to the returned value
+	UnwrapPath []string
+	// Set when the method is paginated and returns a Relay connection
+	Pagination *Pagination
+	// The arguments rendered as InputArgs
+	Args []*Field
+
+	Deprecated        bool
+	DeprecationReason string
+
+	method *protogen.Method
+	// The unwrapped payload, if any
+	payload *Field
+}
+
+// generateSchema renders the schema of a group of services, returning the
+// data it was rendered from
+func (g *Generator) generateSchema(group *schemaGroup, gen *protogen.Plugin) (*TemplateData, error) {
+	gf := gen.NewGeneratedFile(fmt.Sprintf("%s.graphql", group.name), protogen.GoImportPath(""))
+	return g.renderTemplate(group, gf)
+}
+
+func (g *Generator) renderTemplate(group *schemaGroup, gf *protogen.GeneratedFile) (*TemplateData, error) {
+	templateData, err := prepareTemplateData(group, newTypeMapper(g))
+	if err != nil {
+		return nil, err
+	}
+	return templateData, g.template.Execute(gf, templateData)
+}
+
+// prepareTemplateData prepares the schema of a group of services. Types
+// referenced by several services are declared once, and their operations
+// share the schema root.
+func prepareTemplateData(group *schemaGroup, tm *typeMapper) (*TemplateData, error) {
+	for _, svc := range group.services {
+		if err := checkStreamingMethods(svc, tm.opts.Strict); err != nil {
+			return nil, err
+		}
+	}
+	if err := tm.resolveTypeNames(group.files); err != nil {
+		return nil, err
+	}
+	// The files' entities are rendered first, in the order they are declared
+	var sources []string
+	for _, file := range group.files {
+		for _, msg := range file.Messages {
+			if messageAnnotations(msg).Entity {
+				tm.messageType(msg)
+			}
+		}
+		sources = append(sources, file.Desc.Path())
+	}
+
+	var methods []*Method
+	var services []*ServiceData
+	federated := false
+	for _, svc := range group.services {
+		svcMethods := extractMethods(svc, tm)
+		svcFederated := isFederated(svc, tm.opts.FederatedOnly)
+		svcMessages, err := extractMessages(svc, tm)
+		if err != nil {
+			return nil, err
+		}
+		services = append(services, &ServiceData{
+			Name:      subgraphName(svc),
+			Federated: svcFederated,
+			Methods:   svcMethods,
+			Messages:  svcMessages,
+		})
+		methods = append(methods, svcMethods...)
+		federated = federated || svcFederated
+	}
+	if err := checkOperationNames(services); err != nil {
+		return nil, err
+	}
+	messages, err := extractAllMessagesFromFile(group.files[0], methods, tm)
+	if err != nil {
+		return nil, err
+	}
+	data := &TemplateData{
+		Federated:            federated,
+		Subgraph:             group.subgraph,
+		Services:             services,
+		MutationServices:     hasMethodType(methods, OperationMutation),
+		SubscriptionServices: hasMethodType(methods, OperationSubscription),
+		Messages:             messages,
+		Source:               strings.Join(sources, ", "),
+	}
+	if federated {
+		data.Federation = federationLink(messages, len(tm.Connections()) > 0)
+	}
+	data.Scalars = tm.Scalars()
+	data.Directives = tm.Directives()
+	data.Enums = tm.Enums()
+	data.MapEntries = tm.MapEntries()
+	data.Oneofs = tm.Oneofs()
+	data.Inputs = tm.Inputs()
+	data.Connections = tm.Connections()
+	return data, nil
+}
+
+func extractMethods(svc *protogen.Service, tm *typeMapper) []*Method {
+	// Added nil check
+	if svc == nil {
+		return nil
+	}
+
+	// Register the paginated methods first so that their page fields are
+	// left out of every input generated for their request messages
+	paginations := make(map[*protogen.Method]*Pagination)
+	for _, method := range svc.Methods {
+		if method == nil {
+			continue
+		}
+		if items := paginatedList(method); items != nil {
+			paginations[method] = tm.pagination(method, items)
+		}
+	}
+
+	var methods []*Method
+	for _, method := range svc.Methods {
+		// Client-streaming methods have no GraphQL operation
+		if method == nil || method.Desc.IsStreamingClient() {
+			continue
+		}
+
+		// Paginated methods take the Relay connection arguments instead of
+		// their page fields
+		pagination := paginations[method]
+
+		// Extract proper input arguments
+		args := extractInputArgs(method.Input, isUpdateMethod(method), tm)
+		if pagination != nil {
+			args = append(args, connectionArgs(pagination)...)
+		}
+
+		// Decide method type (Query, Mutation or Subscription)
+		methodType := classifyMethod(method, tm.opts.MutationPrefixes)
+
+		m := &Method{
+			Name:      operationName(method, tm.opts.OperationNaming),
+			ProtoName: string(method.Desc.Name()),
+			Type:      string(methodType),
+			InputArgs: formatArgs(args),
+			Args:      args,
+			method:    method,
+		}
+
+		// Return the connection or the payload instead of the response message
+		// wrapping them
+		returned := method.Output
+		if pagination != nil {
+			m.OutputType = pagination.Connection.Name
+			m.Pagination = pagination
+			returned = pagination.items.Message
+		} else if payload := responsePayload(method.Output); payload != nil {
+			m.payload = tm.newField(payload, outputUse)
+			m.OutputType = strings.TrimSuffix(m.payload.TypeRef(), "!")
+			m.UnwrapPath = []string{string(payload.Desc.Name())}
+			returned = payload.Message
+		} else {
+			m.OutputType = tm.messageType(method.Output)
+		}
+
+		// Operations returning a deprecated type are deprecated with it
+		if m.Deprecated, m.DeprecationReason = methodDeprecation(method); !m.Deprecated {
+			m.Deprecated, m.DeprecationReason = messageDeprecation(returned)
+		}
+		m.Comment = deprecatedDescription(method.Comments, m.Deprecated)
+
+		methods = append(methods, m)
+	}
+	return methods
+}
+
+// responsePayload returns the field of a response message that operations
+// should return in place of the message: the field marked with
+// (metadata.v1.payload), or the only field of a *Response wrapper message
+// with a single message field. Entities and other domain messages are
+// returned as they are, so it returns nil for them.
+func responsePayload(msg *protogen.Message) *protogen.Field {
+	if msg == nil || isWellKnownType(msg) || messageAnnotations(msg).Entity {
+		return nil
+	}
+	for _, f := range msg.Fields {
+		if fieldAnnotations(f).Payload {
+			return f
+		}
+	}
+	if strings.HasSuffix(string(msg.Desc.Name()), "Response") && len(msg.Fields) == 1 {
+		f := msg.Fields[0]
+		if f.Message != nil && !f.Desc.IsMap() && !isRealOneof(f) {
+			return f
+		}
+	}
+	return nil
+}
+
+// extractInputArgs returns the arguments of an operation taking input. The
+// input of update methods leaves out IMMUTABLE fields.
+func extractInputArgs(input *protogen.Message, update bool, tm *typeMapper) []*Field {
+	if len(input.Fields) == 0 || isWellKnownType(input) {
+		return nil
+	}
+
+	// Requests with nothing but page fields take no arguments of their own
+	pageOnly := true
+	for _, f := range input.Fields {
+		pageOnly = pageOnly && tm.isPageField(f)
+	}
+	if pageOnly {
+		return nil
+	}
+
+	if tm.opts.InputStyle == InputStyleObject {
+		inputType := tm.inputType(input, update)
+		return []*Field{{Name: "input", GraphQLType: inputType.Name, NonNull: true, input: inputType}}
+	}
+	return extractInputFields(input, update, tm)
+}
+
+// formatArgs renders the arguments of an operation, e.g. (id: String!)
+func formatArgs(args []*Field) string {
+	if len(args) == 0 {
+		return ""
+	}
+	var formatted []string
+	for _, arg := range args {
+		formatted = append(formatted, fmt.Sprintf("%s: %s%s%s", arg.Name, arg.TypeRef(), arg.ConstraintDirective(), arg.DeprecatedDirective()))
+	}
+	return "(" + strings.Join(formatted, ", ") + ")"
+}
+
+func extractMessages(svc *protogen.Service, tm *typeMapper) ([]*Message, error) {
+	// Added nil check to prevent panic
+	if svc == nil {
+		return nil, nil
+	}
+
+	// Track processed message names to avoid duplicates
+	processedMessages := make(map[string]bool)
+
+	var messages []*Message
+	for _, m := range svc.Methods {
+		if m == nil || m.Output == nil || isWellKnownType(m.Output) || m.Desc.IsStreamingClient() {
+			continue
+		}
+
+		// Add the output message itself
+		if !processedMessages[tm.typeName(m.Output.Desc)] {
+			fields, err := extractFields(m.Output, tm)
+			if err != nil {
+				return nil, err
+			}
+			messages = append(messages, &Message{
+				Name:   tm.typeName(m.Output.Desc),
+				Entity: messageAnnotations(m.Output).Entity,
+				Fields: fields,
+			})
+			processedMessages[tm.typeName(m.Output.Desc)] = true
+		}
+
+		// Process fields that are messages
+		for _, f := range m.Output.Fields {
+			if f != nil && f.Message != nil && !isWellKnownType(f.Message) {
+				msgName := tm.typeName(f.Message.Desc)
+				if !processedMessages[msgName] {
+					fields, err := extractFields(f.Message, tm)
+					if err != nil {
+						return nil, err
+					}
+					messages = append(messages, &Message{
+						Name:   msgName,
+						Entity: messageAnnotations(f.Message).Entity,
+						Fields: fields,
+					})
+					processedMessages[msgName] = true
+
+					// Recursively add nested message types
+					if err := addNestedMessages(f.Message, &messages, processedMessages, tm); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+	}
+	return messages, nil
+}
+
+// Recursively add nested message types
+func addNestedMessages(msg *protogen.Message, messages *[]*Message, processed map[string]bool, tm *typeMapper) error {
+	if msg == nil {
+		return nil
+	}
+
+	for _, f := range msg.Fields {
+		if f != nil && f.Message != nil && !isWellKnownType(f.Message) {
+			msgName := tm.typeName(f.Message.Desc)
+			if !processed[msgName] {
+				fields, err := extractFields(f.Message, tm)
+				if err != nil {
+					return err
+				}
+				*messages = append(*messages, &Message{
+					Name:   msgName,
+					Entity: messageAnnotations(f.Message).Entity,
+					Fields: fields,
+				})
+				processed[msgName] = true
+
+				// Recurse for this message's fields
+				if err := addNestedMessages(f.Message, messages, processed, tm); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// extractAllMessagesFromFile converts the messages rendered as object types
+// to GraphQL types: the entities of file and every message the schema refers
+// to, wherever it is declared. Each message is rendered once, in the order
+// it was first referenced.
+func extractAllMessagesFromFile(file *protogen.File, methods []*Method, tm *typeMapper) ([]*Message, error) {
+	// Added nil check to prevent panic
+	if file == nil {
+		return nil, nil
+	}
+
+	var messages []*Message
+	// Extracting fields refers to more messages, growing the list
+	for i := 0; i < len(tm.objects); i++ {
+		msg := tm.objects[i]
+		annotations := messageAnnotations(msg)
+		fields, err := extractFields(msg, tm)
+		if err != nil {
+			return nil, err
+		}
+		message := &Message{
+			Name:             tm.typeName(msg.Desc),
+			Entity:           annotations.Entity,
+			Fields:           fields,
+			ReferenceMethods: referenceMethods(msg, annotations.Provides, methods),
+			Shareable:        annotations.Shareable,
+			Inaccessible:     annotations.Inaccessible,
+			Tags:             annotations.Tags,
+			InterfaceObject:  annotations.InterfaceObject,
+			Comment:          description(msg.Comments),
+			message:          msg,
+		}
+		if !message.Entity && hasKeys(msg) {
+			return nil, fmt.Errorf("%s declares keys but is not an entity: set option (metadata.v1.entity) = true", msg.Desc.FullName())
+		}
+		if message.Entity {
+			keys, err := entityKeys(msg, tm.opts.Naming)
+			if err != nil {
+				return nil, err
+			}
+			message.Keys = keys
+		}
+		messages = append(messages, message)
+	}
+	return messages, nil
+}
+
+// referenceMethods returns the methods of the service being generated that
+// are named in the provides option of msg. Names may be qualified with the
+// service's full name; names not matching a method of any service in the
+// file are reported.
+func referenceMethods(msg *protogen.Message, provides []string, methods []*Method) []*Method {
+	var refs []*Method
+	for _, name := range provides {
+		protoName := name[strings.LastIndex(name, ".")+1:]
+		for _, m := range methods {
+			if m.ProtoName == protoName {
+				refs = append(refs, m)
+			}
+		}
+		if !hasMethod(msg.Desc.ParentFile(), protoName) {
+			log.Printf("Warning: %s provides unknown method %q", msg.Desc.FullName(), name)
+		}
+	}
+	return refs
+}
+
+// hasMethod reports whether any service in file has a method named name
+func hasMethod(file protoreflect.FileDescriptor, name string) bool {
+	services := file.Services()
+	for i := 0; i < services.Len(); i++ {
+		if services.Get(i).Methods().ByName(protoreflect.Name(name)) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// extractFields converts the fields of msg to GraphQL fields. The field sets
+// of the requires, provides_fields and computed_from options are checked and
+// rendered with the generator's naming option, like entity keys.
+func extractFields(msg *protogen.Message, tm *typeMapper) ([]*Field, error) {
+	// Added nil check to prevent panic
+	if msg == nil {
+		return nil, nil
+	}
+
+	var fields []*Field
+	for _, f := range msg.Fields {
+		// A oneof becomes a single union field in place of its members
+		if isRealOneof(f) {
+			if f == f.Oneof.Fields[0] {
+				fields = append(fields, tm.oneofField(f.Oneof, false))
+			}
+			continue
+		}
+
+		if isOmitted(f, outputUse) {
+			continue
+		}
+
+		annotations := fieldAnnotations(f)
+
+		field := tm.newField(f, outputUse)
+		field.Key = annotations.Key
+		field.External = annotations.External
+		requires, err := fieldSelection(f, "requires", msg, annotations.Requires, tm.opts.Naming)
+		if err != nil {
+			return nil, err
+		}
+		provides, err := fieldSelection(f, "provides_fields", f.Message, annotations.Provides, tm.opts.Naming)
+		if err != nil {
+			return nil, err
+		}
+		computedFrom, err := fieldSelection(f, "computed_from", msg, annotations.ComputedFrom, tm.opts.Naming)
+		if err != nil {
+			return nil, err
+		}
+		field.Requires = requires
+		field.Provides = provides
+		field.Shareable = annotations.Shareable
+		field.Inaccessible = annotations.Inaccessible
+		field.OverrideFrom = annotations.OverrideFrom
+		field.Tags = annotations.Tags
+		field.Deprecated, field.DeprecationReason = fieldDeprecation(f)
+		field.ComputedFrom = computedFrom
+		if field.ComputedFrom != "" {
+			tm.useDirective("computed")
+		}
+		field.Comment = deprecatedDescription(f.Comments, field.Deprecated)
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// Operation types a method can be exposed as
+type OperationType string
+
+const (
+	OperationQuery        OperationType = "Query"
+	OperationMutation     OperationType = "Mutation"
+	OperationSubscription OperationType = "Subscription"
+)
+
+// defaultMutationPrefixes are the method name prefixes classified as
+// mutations when nothing else decides the operation type
+var defaultMutationPrefixes = []string{"Create", "Update", "Delete", "Add", "Remove"}
+
+// classifyMethod decides which root operation type a method is exposed
+// under. In order of precedence:
+//   - the (metadata.v1.operation) method option
+//   - server-streaming methods are subscriptions
+//   - idempotency_level NO_SIDE_EFFECTS methods are queries and IDEMPOTENT
+//     methods are mutations
+//   - methods whose name starts with a mutation prefix are mutations
+//   - everything else is a query
+func classifyMethod(method *protogen.Method, extraMutationPrefixes []string) OperationType {
+	switch methodAnnotations(method).Operation {
+	case metadatav1.OperationType_OPERATION_TYPE_QUERY:
+		return OperationQuery
+	case metadatav1.OperationType_OPERATION_TYPE_MUTATION:
+		return OperationMutation
+	case metadatav1.OperationType_OPERATION_TYPE_SUBSCRIPTION:
+		return OperationSubscription
+	}
+
+	if method.Desc.IsStreamingServer() {
+		return OperationSubscription
+	}
+
+	if opts, ok := method.Desc.Options().(*descriptorpb.MethodOptions); ok {
+		switch opts.GetIdempotencyLevel() {
+		case descriptorpb.MethodOptions_NO_SIDE_EFFECTS:
+			return OperationQuery
+		case descriptorpb.MethodOptions_IDEMPOTENT:
+			return OperationMutation
+		}
+	}
+
+	name := string(method.Desc.Name())
+	for _, prefixes := range [][]string{defaultMutationPrefixes, extraMutationPrefixes} {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(name, prefix) {
+				return OperationMutation
+			}
+		}
+	}
+	return OperationQuery
+}
+
+// checkStreamingMethods reports the client-streaming and bidirectional
+// streaming methods of svc, which can't be exposed as GraphQL operations:
+// they are skipped with a warning, or fail the generation in strict mode
+func checkStreamingMethods(svc *protogen.Service, strict bool) error {
+	for _, method := range svc.Methods {
+		if !method.Desc.IsStreamingClient() {
+			continue
+		}
+		kind := "client-streaming"
+		if method.Desc.IsStreamingServer() {
+			kind = "bidirectional streaming"
+		}
+		if strict {
+			return fmt.Errorf("%s is a %s method, which has no GraphQL operation", method.Desc.FullName(), kind)
+		}
+		log.Printf("Warning: skipping %s method %s", kind, method.Desc.FullName())
+	}
+	return nil
+}
+
+// hasMethodType reports whether any of the methods is of the given type
+func hasMethodType(methods []*Method, methodType OperationType) bool {
+	for _, method := range methods {
+		if method.Type == string(methodType) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,3403 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/bufbuild/protocompile"
+	metadatav1 "github.com/fraser-isbester/federated-gql/gen/go/metadata/v1"
+	gengo "google.golang.org/protobuf/cmd/protoc-gen-go/internal_gengo"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// runGenerator compiles the given proto sources and runs the generator over
+// all of them, returning the generated file contents keyed by file name.
+func runGenerator(t *testing.T, opts Options, sources map[string]string) map[string]string {
+	t.Helper()
+	return runPlugin(t, opts, sources, (*Generator).Generate)
+}
+
+// runPlugin compiles the given proto sources and runs generate over them,
+// returning the generated files by name
+func runPlugin(t *testing.T, opts Options, sources map[string]string, generate func(*Generator, *protogen.Plugin) error) map[string]string {
+	t.Helper()
+
+	plugin := newTestPlugin(t, sources)
+	g, err := NewGenerator(opts)
+	if err != nil {
+		t.Fatalf("failed to create generator: %v", err)
+	}
+	if err := generate(g, plugin); err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+
+	resp := plugin.Response()
+	if resp.Error != nil {
+		t.Fatalf("plugin returned error: %s", resp.GetError())
+	}
+	out := make(map[string]string)
+	for _, f := range resp.File {
+		out[f.GetName()] = f.GetContent()
+	}
+	return out
+}
+
+// newTestPlugin compiles the given proto sources into a plugin request for
+// all of them, as protoc would send it
+func newTestPlugin(t *testing.T, sources map[string]string) *protogen.Plugin {
+	t.Helper()
+
+	var names []string
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(protocompile.CompositeResolver{
+			&protocompile.SourceResolver{Accessor: protocompile.SourceAccessorFromMap(sources)},
+			&protocompile.SourceResolver{ImportPaths: []string{"../../../proto"}},
+			// Dependencies such as google/api/field_behavior.proto are linked in
+			protocompile.ResolverFunc(func(path string) (protocompile.SearchResult, error) {
+				fd, err := protoregistry.GlobalFiles.FindFileByPath(path)
+				if err != nil {
+					return protocompile.SearchResult{}, err
+				}
+				return protocompile.SearchResult{Desc: fd}, nil
+			}),
+		}),
+		SourceInfoMode: protocompile.SourceInfoStandard,
+	}
+	files, err := compiler.Compile(context.Background(), names...)
+	if err != nil {
+		t.Fatalf("failed to compile protos: %v", err)
+	}
+
+	// protoc sends every file in dependency order
+	var protoFiles []*descriptorpb.FileDescriptorProto
+	seen := make(map[string]bool)
+	var add func(fd protoreflect.FileDescriptor)
+	add = func(fd protoreflect.FileDescriptor) {
+		if seen[fd.Path()] {
+			return
+		}
+		seen[fd.Path()] = true
+		imports := fd.Imports()
+		for i := 0; i < imports.Len(); i++ {
+			add(imports.Get(i).FileDescriptor)
+		}
+		fdp := protodesc.ToFileDescriptorProto(fd)
+		// protogen insists on a Go package even though no Go code is emitted
+		if fdp.GetOptions().GetGoPackage() == "" {
+			if fdp.Options == nil {
+				fdp.Options = &descriptorpb.FileOptions{}
+			}
+			fdp.Options.GoPackage = proto.String("example.com/" + path.Dir(fdp.GetName()))
+		}
+		protoFiles = append(protoFiles, fdp)
+	}
+	for _, f := range files {
+		add(f)
+	}
+
+	plugin, err := protogen.Options{}.New(&pluginpb.CodeGeneratorRequest{
+		FileToGenerate: names,
+		ProtoFile:      protoFiles,
+	})
+	if err != nil {
+		t.Fatalf("failed to create plugin: %v", err)
+	}
+	return plugin
+}
+
+// generateProtoGo runs protoc-gen-go over the given proto sources, returning
+// the generated files by name
+func generateProtoGo(t *testing.T, sources map[string]string) map[string]string {
+	t.Helper()
+
+	plugin := newTestPlugin(t, sources)
+	for _, f := range plugin.Files {
+		if f.Generate {
+			gengo.GenerateFile(plugin, f)
+		}
+	}
+	resp := plugin.Response()
+	if resp.Error != nil {
+		t.Fatalf("protoc-gen-go returned error: %s", resp.GetError())
+	}
+	out := make(map[string]string)
+	for _, f := range resp.File {
+		out[f.GetName()] = f.GetContent()
+	}
+	return out
+}
+
+// connectClientStub returns the Connect client interfaces protoc-gen-connect-go
+// generates for the services of file, with the methods the resolvers call
+func connectClientStub(file *protogen.File) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "package %sconnect\n\n", file.GoPackageName)
+	fmt.Fprintf(&b, "import (\n\t\"context\"\n\n\t\"connectrpc.com/connect\"\n\tpb %q\n)\n", string(file.GoImportPath))
+	for _, svc := range file.Services {
+		fmt.Fprintf(&b, "\ntype %sClient interface {\n", svc.GoName)
+		for _, m := range svc.Methods {
+			response := "*connect.Response[pb.%s]"
+			if m.Desc.IsStreamingServer() {
+				response = "*connect.ServerStreamForClient[pb.%s]"
+			}
+			fmt.Fprintf(&b, "\t%s(context.Context, *connect.Request[pb.%s]) ("+response+", error)\n", m.GoName, m.Input.GoIdent.GoName, m.Output.GoIdent.GoName)
+		}
+		b.WriteString("}\n")
+	}
+	return b.String()
+}
+
+// goModuleStubs are stubs of the packages generated code imports that this
+// module doesn't depend on, with the declarations the generated code uses
+var goModuleStubs = map[string]string{
+	"stubs/connect/go.mod": "module connectrpc.com/connect\n\ngo 1.24.0\n",
+	"stubs/connect/connect.go": `package connect
+
+type Request[T any] struct{ Msg *T }
+
+func NewRequest[T any](message *T) *Request[T] { return &Request[T]{Msg: message} }
+
+type Response[T any] struct{ Msg *T }
+
+type ServerStreamForClient[T any] struct{ msg *T }
+
+func (s *ServerStreamForClient[T]) Receive() bool { return false }
+func (s *ServerStreamForClient[T]) Msg() *T       { return s.msg }
+func (s *ServerStreamForClient[T]) Err() error    { return nil }
+func (s *ServerStreamForClient[T]) Close() error  { return nil }
+`,
+	"stubs/gqlgen/go.mod": "module github.com/99designs/gqlgen\n\ngo 1.24.0\n",
+	"stubs/gqlgen/graphql/graphql.go": `package graphql
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+type Marshaler interface{ MarshalGQL(w io.Writer) }
+
+type ContextMarshaler interface {
+	MarshalGQLContext(ctx context.Context, w io.Writer) error
+}
+
+type WriterFunc func(writer io.Writer)
+
+func (f WriterFunc) MarshalGQL(w io.Writer) { f(w) }
+
+type ContextWriterFunc func(ctx context.Context, writer io.Writer) error
+
+func (f ContextWriterFunc) MarshalGQLContext(ctx context.Context, w io.Writer) error { return f(ctx, w) }
+
+func MarshalString(s string) Marshaler {
+	return WriterFunc(func(w io.Writer) { io.WriteString(w, strconv.Quote(s)) })
+}
+func UnmarshalString(v any) (string, error) { return "", nil }
+func MarshalFloat(f float64) Marshaler      { return WriterFunc(func(io.Writer) {}) }
+func UnmarshalFloat(v any) (float64, error) { return 0, nil }
+func MarshalInt64(i int64) Marshaler        { return WriterFunc(func(io.Writer) {}) }
+func UnmarshalInt64(v any) (int64, error) {
+	switch v := v.(type) {
+	case int:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	}
+	return 0, fmt.Errorf("%T is not an int", v)
+}
+func UnmarshalUint64(v any) (uint64, error) { return 0, nil }
+`,
+}
+
+// vetGoModule writes a Go module example.com holding files, Go sources by
+// their path in the module, along with the code protoc-gen-go and
+// protoc-gen-connect-go generate for the proto sources, and vets it. The
+// generated code is type-checked against the stubs of the gqlgen models
+// and resolver interfaces in files. It returns the module directory.
+func vetGoModule(t *testing.T, sources, files map[string]string) string {
+	t.Helper()
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not found")
+	}
+
+	dir := t.TempDir()
+	write := func(name, content string) {
+		name = filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name, content := range goModuleStubs {
+		write(name, content)
+	}
+	for name, content := range generateProtoGo(t, sources) {
+		if rel, ok := strings.CutPrefix(name, "example.com/"); ok {
+			write(rel, content)
+		}
+	}
+	for _, f := range newTestPlugin(t, sources).Files {
+		if rel, ok := strings.CutPrefix(string(f.GoImportPath), "example.com/"); ok && f.Generate && len(f.Services) > 0 {
+			write(path.Join(rel, string(f.GoPackageName)+"connect", "connect.go"), connectClientStub(f))
+		}
+	}
+	for name, content := range files {
+		write(name, content)
+	}
+
+	// Depend on the protobuf module this one is built with, and the
+	// metadata options the proto sources import
+	protobuf := ""
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, dep := range info.Deps {
+			if dep.Path == "google.golang.org/protobuf" {
+				protobuf = dep.Version
+			}
+		}
+	}
+	genGo, err := filepath.Abs("../../../gen/go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	write("go.mod", fmt.Sprintf(`module example.com
+
+go 1.24.0
+
+require (
+	connectrpc.com/connect v1.18.1
+	github.com/99designs/gqlgen v0.17.66
+	github.com/fraser-isbester/federated-gql/gen/go v0.0.0
+	google.golang.org/protobuf %s
+)
+
+replace (
+	connectrpc.com/connect => ./stubs/connect
+	github.com/99designs/gqlgen => ./stubs/gqlgen
+	github.com/fraser-isbester/federated-gql/gen/go => %s
+)
+`, protobuf, genGo))
+	sum, err := os.ReadFile("../go.sum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	write("go.sum", string(sum))
+
+	runGo(t, dir, "vet", "./...")
+	return dir
+}
+
+// runGo runs the go command in dir, failing the test if it fails, and
+// returns its output
+func runGo(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("go %s failed: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return string(out)
+}
+
+func TestMetadataAnnotations(t *testing.T) {
+	out := runGenerator(t, Options{}, map[string]string{
+		"shop/v1/shop.proto": `
+syntax = "proto3";
+package shop.v1;
+
+import "metadata/v1/metadata.proto";
+
+service ShopService {
+  rpc GetWidget(GetWidgetRequest) returns (Widget) {}
+}
+
+message GetWidgetRequest {
+  string widget_id = 1;
+}
+
+message Widget {
+  option (metadata.v1.entity) = true;
+
+  string sku = 1 [(metadata.v1.key) = true];
+  string order_id = 2;
+  string owner_id = 3 [(metadata.v1.external) = true];
+}
+
+message Order {
+  string order_id = 1;
+  string product_id = 2;
+}
+`,
+	})
+
+	schema, ok := out["shop.v1.ShopService.graphql"]
+	if !ok {
+		t.Fatalf("expected shop.v1.ShopService.graphql to be generated, got %v", out)
+	}
+
+	tests := []struct {
+		name    string
+		snippet string
+		want    bool
+	}{
+		{
+			name:    "annotated entity is keyed by its annotated key field",
+			snippet: `type Widget @key(fields: "sku")`,
+			want:    true,
+		},
+		{
+			name:    "external annotation is rendered",
+			snippet: `owner_id: String! @external`,
+			want:    true,
+		},
+		{
+			name:    "unannotated message is not an entity",
+			snippet: `type Order @key`,
+			want:    false,
+		},
+		{
+			name:    "_id suffix no longer implies a key",
+			snippet: `@key(fields: "order_id")`,
+			want:    false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := strings.Contains(schema, tc.snippet); got != tc.want {
+				t.Errorf("expected contains(%q) = %v, got %v\n%s", tc.snippet, tc.want, got, schema)
+			}
+		})
+	}
+}
+
+func TestGetExtensionUnknownFields(t *testing.T) {
+	// (metadata.v1.entity) = true encoded as an unrecognized field
+	opts := &descriptorpb.MessageOptions{}
+	opts.ProtoReflect().SetUnknown(protowire.AppendVarint(protowire.AppendTag(nil, 50001, protowire.VarintType), 1))
+
+	if !getExtension(opts, metadatav1.E_Entity).(bool) {
+		t.Errorf("expected entity option to be resolved from unknown fields")
+	}
+	if getExtension(opts, metadatav1.E_Provides).([]string) != nil {
+		t.Errorf("expected unset provides option to be empty")
+	}
+	if getExtension(nil, metadatav1.E_Entity).(bool) {
+		t.Errorf("expected nil options to resolve to false")
+	}
+}
+
+const scalarsProto = `
+syntax = "proto3";
+package scalars.v1;
+
+import "metadata/v1/metadata.proto";
+
+service ScalarService {
+  rpc GetAll(GetAllRequest) returns (All) {}
+}
+
+message GetAllRequest {
+  int64 since = 1;
+}
+
+message All {
+  option (metadata.v1.entity) = true;
+
+  double d = 1 [(metadata.v1.key) = true];
+  float f = 2;
+  int32 i32 = 3;
+  sint32 s32 = 4;
+  uint32 u32 = 5;
+  int64 i64 = 6;
+  sfixed64 sf64 = 7;
+  uint64 u64 = 8;
+  fixed64 f64 = 9;
+  bool b = 10;
+  string s = 11;
+  bytes raw = 12;
+}
+`
+
+func TestScalarMapping(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     Options
+		contains []string
+		excludes []string
+	}{
+		{
+			name: "default mapping",
+			contains: []string{
+				"GetAll(since: Int64): All",
+				"d: Float!",
+				"f: Float!",
+				"i32: Int!",
+				"s32: Int!",
+				"u32: Int64!",
+				"i64: Int64!",
+				"sf64: Int64!",
+				"u64: UInt64!",
+				"f64: UInt64!",
+				"b: Boolean!",
+				"s: String!",
+				"raw: Base64!",
+				"scalar Int64\n",
+				"scalar UInt64\n",
+				"scalar Base64\n",
+			},
+		},
+		{
+			name: "overridden mapping",
+			opts: Options{Scalars: []string{"int64:String", "sfixed64:String", "uint32:String", "bytes:Bytes"}},
+			contains: []string{
+				"GetAll(since: String): All",
+				"i64: String!",
+				"raw: Bytes!",
+				"scalar Bytes\n",
+				"scalar UInt64\n",
+			},
+			excludes: []string{
+				"scalar Int64",
+				"scalar Base64",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			schema := runGenerator(t, tc.opts, map[string]string{"scalars/v1/scalars.proto": scalarsProto})["scalars.v1.ScalarService.graphql"]
+			for _, want := range tc.contains {
+				if !strings.Contains(schema, want) {
+					t.Errorf("expected schema to contain %q\n%s", want, schema)
+				}
+			}
+			for _, unwanted := range tc.excludes {
+				if strings.Contains(schema, unwanted) {
+					t.Errorf("expected schema not to contain %q\n%s", unwanted, schema)
+				}
+			}
+		})
+	}
+}
+
+func TestInvalidScalarOverride(t *testing.T) {
+	for _, value := range []string{"int64", "int64:", "varchar:String"} {
+		if _, err := NewGenerator(Options{Scalars: []string{value}}); err == nil {
+			t.Errorf("expected error for scalar override %q", value)
+		}
+	}
+}
+
+const enumsProto = `
+syntax = "proto3";
+package catalog.v1;
+
+import "metadata/v1/metadata.proto";
+
+service CatalogService {
+  rpc GetItem(GetItemRequest) returns (Item) {}
+}
+
+message GetItemRequest {
+  string item_id = 1;
+  Visibility visibility = 2;
+}
+
+// Visibility controls who can see an item.
+enum Visibility {
+  VISIBILITY_UNSPECIFIED = 0;
+  // Visible to everyone.
+  VISIBILITY_PUBLIC = 1;
+  VISIBILITY_PRIVATE = 2;
+  VISIBILITY_HIDDEN = 3 [deprecated = true];
+}
+
+message Item {
+  option (metadata.v1.entity) = true;
+
+  enum Status {
+    STATUS_UNSPECIFIED = 0;
+    STATUS_ACTIVE = 1;
+    STATUS_ARCHIVED = 2;
+  }
+
+  enum Kind {
+    PHYSICAL = 0;
+    DIGITAL = 1;
+  }
+
+  string item_id = 1 [(metadata.v1.key) = true];
+  Status status = 2;
+  Kind kind = 3;
+}
+
+enum Unused {
+  UNUSED_UNSPECIFIED = 0;
+}
+`
+
+func TestEnums(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     Options
+		contains []string
+		excludes []string
+	}{
+		{
+			name: "enums are generated with prefixes stripped",
+			contains: []string{
+				"GetItem(item_id: String, visibility: Visibility): Item",
+				"status: Item_Status!",
+				"kind: Item_Kind!",
+				"\"\"\"\nVisibility controls who can see an item.\n\"\"\"\nenum Visibility {\n  UNSPECIFIED\n",
+				"  \"\"\"\n  Visible to everyone.\n  \"\"\"\n  PUBLIC\n",
+				"  HIDDEN @deprecated\n",
+				"enum Item_Status {\n  UNSPECIFIED\n  ACTIVE\n  ARCHIVED\n}",
+				"enum Item_Kind {\n  PHYSICAL\n  DIGITAL\n}",
+			},
+			excludes: []string{
+				"enum Unused",
+			},
+		},
+		{
+			name: "unspecified values can be dropped",
+			opts: Options{EnumDropUnspecified: true},
+			contains: []string{
+				"enum Item_Status {\n  ACTIVE\n  ARCHIVED\n}",
+				"enum Item_Kind {\n  PHYSICAL\n  DIGITAL\n}",
+				// Unset fields have no value left, so they are nullable
+				"GetItem(item_id: String, visibility: Visibility): Item",
+				"status: Item_Status\n",
+				"kind: Item_Kind!",
+			},
+			excludes: []string{
+				"UNSPECIFIED",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			schema := runGenerator(t, tc.opts, map[string]string{"catalog/v1/catalog.proto": enumsProto})["catalog.v1.CatalogService.graphql"]
+			for _, want := range tc.contains {
+				if !strings.Contains(schema, want) {
+					t.Errorf("expected schema to contain %q\n%s", want, schema)
+				}
+			}
+			for _, unwanted := range tc.excludes {
+				if strings.Contains(schema, unwanted) {
+					t.Errorf("expected schema not to contain %q\n%s", unwanted, schema)
+				}
+			}
+		})
+	}
+}
+
+const collectionsProto = `
+syntax = "proto3";
+package inventory.v1;
+
+import "metadata/v1/metadata.proto";
+
+service InventoryService {
+  rpc GetShelf(GetShelfRequest) returns (Shelf) {}
+}
+
+message GetShelfRequest {
+  repeated string shelf_ids = 1;
+  map<string, string> filters = 2;
+}
+
+message Shelf {
+  option (metadata.v1.entity) = true;
+
+  string shelf_id = 1 [(metadata.v1.key) = true];
+  repeated Product products = 2;
+  repeated int32 bins = 3;
+  map<string, string> labels = 4;
+  map<int32, Product> products_by_slot = 5;
+}
+
+message Product {
+  string product_id = 1;
+}
+`
+
+func TestListsAndMaps(t *testing.T) {
+	schema := runGenerator(t, Options{}, map[string]string{"inventory/v1/inventory.proto": collectionsProto})["inventory.v1.InventoryService.graphql"]
+
+	for _, want := range []string{
+		"GetShelf(shelf_ids: [String!], filters: [GetShelfRequestFiltersEntryInput!]): Shelf",
+		"products: [Product!]!",
+		"bins: [Int!]!",
+		"labels: [ShelfLabelsEntry!]!",
+		"products_by_slot: [ShelfProductsBySlotEntry!]!",
+		"type ShelfLabelsEntry {\n  key: String!\n  value: String!\n}",
+		"type ShelfProductsBySlotEntry {\n  key: Int!\n  value: Product!\n}",
+		"input GetShelfRequestFiltersEntryInput {\n  key: String!\n  value: String!\n}",
+	} {
+		if !strings.Contains(schema, want) {
+			t.Errorf("expected schema to contain %q\n%s", want, schema)
+		}
+	}
+	if strings.Contains(schema, "input ShelfLabelsEntryInput") {
+		t.Errorf("expected output-only map entry to have no input type\n%s", schema)
+	}
+}
+
+const wellKnownTypesProto = `
+syntax = "proto3";
+package events.v1;
+
+import "google/protobuf/duration.proto";
+import "google/protobuf/empty.proto";
+import "google/protobuf/field_mask.proto";
+import "google/protobuf/struct.proto";
+import "google/protobuf/timestamp.proto";
+import "google/protobuf/wrappers.proto";
+import "metadata/v1/metadata.proto";
+
+service EventService {
+  rpc GetEvent(GetEventRequest) returns (Event) {}
+  rpc Ping(google.protobuf.Empty) returns (google.protobuf.Empty) {}
+}
+
+message GetEventRequest {
+  string event_id = 1;
+  google.protobuf.FieldMask read_mask = 2;
+  google.protobuf.Int64Value version = 3;
+}
+
+message Event {
+  option (metadata.v1.entity) = true;
+
+  string event_id = 1 [(metadata.v1.key) = true];
+  google.protobuf.Timestamp start_time = 2;
+  google.protobuf.Duration length = 3;
+  google.protobuf.StringValue nickname = 4;
+  google.protobuf.DoubleValue rating = 5;
+  google.protobuf.UInt32Value attendees = 6;
+  google.protobuf.Struct attributes = 7;
+  google.protobuf.Value extra = 8;
+  repeated google.protobuf.Timestamp reminders = 9;
+}
+`
+
+func TestWellKnownTypes(t *testing.T) {
+	schema := runGenerator(t, Options{}, map[string]string{"events/v1/events.proto": wellKnownTypesProto})["events.v1.EventService.graphql"]
+
+	for _, want := range []string{
+		"GetEvent(event_id: String, read_mask: FieldMask, version: Int64): Event",
+		"Ping: Boolean",
+		"start_time: DateTime\n",
+		"length: Duration\n",
+		"nickname: String\n",
+		"rating: Float\n",
+		"attendees: Int64\n",
+		"attributes: JSON\n",
+		"extra: JSON\n",
+		"reminders: [DateTime!]!",
+	} {
+		if !strings.Contains(schema, want) {
+			t.Errorf("expected schema to contain %q\n%s", want, schema)
+		}
+	}
+
+	// Every scalar is declared exactly once
+	for _, scalar := range []string{"DateTime", "Duration", "JSON", "FieldMask", "Int64"} {
+		if n := strings.Count(schema, "scalar "+scalar+"\n"); n != 1 {
+			t.Errorf("expected scalar %s to be declared once, got %d\n%s", scalar, n, schema)
+		}
+	}
+	for _, unwanted := range []string{"Timestamp", "StringValue", "scalar Boolean", "scalar String"} {
+		if strings.Contains(schema, unwanted) {
+			t.Errorf("expected schema not to contain %q\n%s", unwanted, schema)
+		}
+	}
+}
+
+const oneofsProto = `
+syntax = "proto3";
+package search.v1;
+
+import "google/protobuf/timestamp.proto";
+import "metadata/v1/metadata.proto";
+
+service SearchService {
+  rpc Search(SearchRequest) returns (Result) {}
+}
+
+message SearchRequest {
+  // What to search by.
+  oneof filter {
+    string name = 1;
+    string tag = 2;
+  }
+  int32 limit = 3;
+  optional string cursor = 4;
+}
+
+message Result {
+  option (metadata.v1.entity) = true;
+
+  string result_id = 1 [(metadata.v1.key) = true];
+  // The matched document.
+  oneof match {
+    Article article = 2;
+    Video video = 3;
+    // A plain text snippet.
+    string snippet = 4;
+    google.protobuf.Timestamp seen_at = 5;
+    Article pinned_article = 6;
+  }
+}
+
+message Article {
+  string title = 1;
+}
+
+message Video {
+  string url = 1;
+}
+`
+
+func TestOneofs(t *testing.T) {
+	schema := runGenerator(t, Options{}, map[string]string{"search/v1/search.proto": oneofsProto})["search.v1.SearchService.graphql"]
+
+	for _, want := range []string{
+		"Search(filter: SearchRequestFilterInput, limit: Int, cursor: String): Result",
+		"\"\"\"\n  The matched document.\n  \"\"\"\n  match: ResultMatch\n",
+		"union ResultMatch = Article | Video | ResultSnippet | ResultSeenAt | ResultPinnedArticle",
+		"type ResultSnippet {\n  \"\"\"\n  A plain text snippet.\n  \"\"\"\n  snippet: String!\n}",
+		"type ResultSeenAt {\n  seen_at: DateTime!\n}",
+		"type ResultPinnedArticle {\n  pinned_article: Article!\n}",
+		"\"\"\"\nWhat to search by.\n\"\"\"\ninput SearchRequestFilterInput @oneOf {\n  name: String\n  tag: String\n}",
+	} {
+		if !strings.Contains(schema, want) {
+			t.Errorf("expected schema to contain %q\n%s", want, schema)
+		}
+	}
+	for _, unwanted := range []string{
+		"  article: Article",
+		"  snippet: String!\n  \n",
+		"input ResultMatchInput",
+	} {
+		if strings.Contains(schema, unwanted) {
+			t.Errorf("expected schema not to contain %q\n%s", unwanted, schema)
+		}
+	}
+}
+
+const inputsProto = `
+syntax = "proto3";
+package orders.v1;
+
+import "metadata/v1/metadata.proto";
+
+service OrderService {
+  rpc CreateOrder(CreateOrderRequest) returns (Order) {}
+}
+
+// CreateOrderRequest places a new order.
+message CreateOrderRequest {
+  Address shipping_address = 1;
+  repeated LineItem items = 2;
+  string note = 3;
+}
+
+// A postal address.
+message Address {
+  string street = 1;
+  Address billing = 2;
+}
+
+message LineItem {
+  message Options {
+    bool gift_wrap = 1;
+  }
+
+  string sku = 1;
+  int32 quantity = 2;
+  Options options = 3;
+}
+
+// Already takes the LineItemInput name.
+message LineItemInput {
+  string raw = 1;
+}
+
+message Order {
+  option (metadata.v1.entity) = true;
+
+  string order_id = 1 [(metadata.v1.key) = true];
+  Address shipping_address = 2;
+}
+`
+
+func TestInputTypes(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     Options
+		contains []string
+		excludes []string
+	}{
+		{
+			name: "flattened arguments reference input types",
+			contains: []string{
+				"CreateOrder(shipping_address: AddressInput, items: [LineItemInput2!], note: String): Order",
+				"\"\"\"\nA postal address.\n\"\"\"\ninput AddressInput {\n  street: String\n  billing: AddressInput\n}",
+				"input LineItemInput2 {\n  sku: String\n  quantity: Int\n  options: OptionsInput\n}",
+				"input OptionsInput {\n  gift_wrap: Boolean\n}",
+				"shipping_address: Address\n",
+			},
+			excludes: []string{
+				"input CreateOrderRequestInput",
+				"input OrderInput",
+			},
+		},
+		{
+			name: "object style passes a single input argument",
+			opts: Options{InputStyle: InputStyleObject},
+			contains: []string{
+				"CreateOrder(input: CreateOrderRequestInput!): Order",
+				"\"\"\"\nCreateOrderRequest places a new order.\n\"\"\"\ninput CreateOrderRequestInput {\n  shipping_address: AddressInput\n  items: [LineItemInput2!]\n  note: String\n}",
+				"input AddressInput {",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			schema := runGenerator(t, tc.opts, map[string]string{"orders/v1/orders.proto": inputsProto})["orders.v1.OrderService.graphql"]
+			for _, want := range tc.contains {
+				if !strings.Contains(schema, want) {
+					t.Errorf("expected schema to contain %q\n%s", want, schema)
+				}
+			}
+			for _, unwanted := range tc.excludes {
+				if strings.Contains(schema, unwanted) {
+					t.Errorf("expected schema not to contain %q\n%s", unwanted, schema)
+				}
+			}
+		})
+	}
+
+	if _, err := NewGenerator(Options{InputStyle: "nested"}); err == nil {
+		t.Errorf("expected error for invalid input style")
+	}
+}
+
+const operationsProto = `
+syntax = "proto3";
+package posts.v1;
+
+import "metadata/v1/metadata.proto";
+
+service PostService {
+  rpc GetPost(PostRequest) returns (Post) {}
+  rpc CreatePost(PostRequest) returns (Post) {}
+  rpc ArchivePost(PostRequest) returns (Post) {}
+  rpc PublishPost(PostRequest) returns (Post) {
+    option (metadata.v1.operation) = OPERATION_TYPE_MUTATION;
+  }
+  rpc DeleteStalePosts(PostRequest) returns (Post) {
+    option (metadata.v1.operation) = OPERATION_TYPE_QUERY;
+  }
+  rpc BatchGetPosts(PostRequest) returns (Post) {
+    option idempotency_level = NO_SIDE_EFFECTS;
+  }
+  rpc UpdateSearchIndex(PostRequest) returns (Post) {
+    option idempotency_level = NO_SIDE_EFFECTS;
+  }
+  rpc BatchTouchPosts(PostRequest) returns (Post) {
+    option idempotency_level = IDEMPOTENT;
+  }
+  rpc WatchPosts(PostRequest) returns (stream Post) {}
+}
+
+message PostRequest {
+  string post_id = 1;
+}
+
+message Post {
+  string post_id = 1;
+}
+`
+
+func TestMethodClassification(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		want map[string]OperationType
+	}{
+		{
+			name: "default classification",
+			want: map[string]OperationType{
+				"GetPost":           OperationQuery,
+				"CreatePost":        OperationMutation,
+				"ArchivePost":       OperationQuery,
+				"PublishPost":       OperationMutation,
+				"DeleteStalePosts":  OperationQuery,
+				"BatchGetPosts":     OperationQuery,
+				"UpdateSearchIndex": OperationQuery,
+				"BatchTouchPosts":   OperationMutation,
+				"WatchPosts":        OperationSubscription,
+			},
+		},
+		{
+			name: "extra mutation prefixes",
+			opts: Options{MutationPrefixes: []string{"Archive"}},
+			want: map[string]OperationType{
+				"ArchivePost": OperationMutation,
+				"GetPost":     OperationQuery,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			schema := runGenerator(t, tc.opts, map[string]string{"posts/v1/posts.proto": operationsProto})["posts.v1.PostService.graphql"]
+
+			for _, root := range []string{"query: Query", "mutation: Mutation", "subscription: Subscription"} {
+				if !strings.Contains(schema, root) {
+					t.Errorf("expected schema root to contain %q\n%s", root, schema)
+				}
+			}
+
+			for method, want := range tc.want {
+				start := strings.Index(schema, "extend type "+string(want))
+				if start < 0 {
+					t.Fatalf("expected schema to extend %s\n%s", want, schema)
+				}
+				block := schema[start:]
+				block = block[:strings.Index(block, "}")]
+				if !strings.Contains(block, method+"(") {
+					t.Errorf("expected %s to be a %s\n%s", method, want, schema)
+				}
+			}
+		})
+	}
+}
+
+const namingProto = `
+syntax = "proto3";
+package shop.v1;
+
+import "metadata/v1/metadata.proto";
+
+service ProductService {
+  rpc GetProduct(GetProductRequest) returns (Product) {}
+  rpc ListProducts(GetProductRequest) returns (Product) {}
+  rpc CreateProduct(GetProductRequest) returns (Product) {}
+  rpc Getaway(GetProductRequest) returns (Product) {}
+  rpc FetchProduct(GetProductRequest) returns (Product) {
+    option (metadata.v1.operation_name) = "productByID";
+  }
+}
+
+message GetProductRequest {
+  string product_id = 1;
+}
+
+message Product {
+  option (metadata.v1.entity) = true;
+
+  string product_id = 1 [(metadata.v1.key) = true];
+  string display_name = 2 [json_name = "title"];
+  repeated string image_urls = 3;
+  repeated string tag_ids = 4;
+  string sku = 5 [(metadata.v1.field_name) = "SKU"];
+}
+`
+
+func TestNaming(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     Options
+		contains []string
+	}{
+		{
+			name: "proto naming",
+			contains: []string{
+				"GetProduct(product_id: String): Product",
+				"ListProducts(product_id: String): Product",
+				"productByID(product_id: String): Product",
+				`type Product @key(fields: "product_id")`,
+				"display_name: String!",
+				"SKU: String!",
+			},
+		},
+		{
+			name: "json names",
+			opts: Options{Naming: NamingJSONName, OperationNaming: OperationNamingLowerCamel},
+			contains: []string{
+				"getProduct(productId: String): Product",
+				"createProduct(productId: String): Product",
+				`type Product @key(fields: "productId")`,
+				"title: String!",
+				"imageUrls: [String!]!",
+				"tagIds: [String!]!",
+			},
+		},
+		{
+			name: "lowerCamel naming matching the gateway schema",
+			opts: Options{Naming: NamingLowerCamel, OperationNaming: OperationNamingStripVerbs},
+			contains: []string{
+				"product(productID: String): Product",
+				"products(productID: String): Product",
+				"createProduct(productID: String): Product",
+				"getaway(productID: String): Product",
+				"productByID(productID: String): Product",
+				`type Product @key(fields: "productID")`,
+				"displayName: String!",
+				"imageURLs: [String!]!",
+				"tagIDs: [String!]!",
+				"SKU: String!",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			schema := runGenerator(t, tc.opts, map[string]string{"shop/v1/shop.proto": namingProto})["shop.v1.ProductService.graphql"]
+			for _, want := range tc.contains {
+				if !strings.Contains(schema, want) {
+					t.Errorf("expected schema to contain %q\n%s", want, schema)
+				}
+			}
+		})
+	}
+
+	for _, opts := range []Options{{Naming: "camel"}, {OperationNaming: "verbless"}} {
+		if _, err := NewGenerator(opts); err == nil {
+			t.Errorf("expected error for invalid naming options %+v", opts)
+		}
+	}
+
+	// Operations of the same service can't share a root field either, once
+	// verbs are stripped or names overridden
+	collisions := []struct {
+		rpcs string
+		opts Options
+		want string
+	}{
+		{
+			rpcs: `
+  rpc GetOrder(GetOrderRequest) returns (Order) {}
+  rpc ListOrder(GetOrderRequest) returns (Order) {}`,
+			opts: Options{OperationNaming: OperationNamingStripVerbs},
+			want: `shop.v1.OrderService.GetOrder and shop.v1.OrderService.ListOrder both generate Query field "order"`,
+		},
+		{
+			rpcs: `
+  rpc GetOrder(GetOrderRequest) returns (Order) {}
+  rpc FetchOrder(GetOrderRequest) returns (Order) {
+    option (metadata.v1.operation_name) = "GetOrder";
+  }`,
+			want: `shop.v1.OrderService.GetOrder and shop.v1.OrderService.FetchOrder both generate Query field "GetOrder"`,
+		},
+	}
+	for _, tc := range collisions {
+		plugin := newTestPlugin(t, map[string]string{"shop/v1/orders.proto": `
+syntax = "proto3";
+package shop.v1;
+
+import "metadata/v1/metadata.proto";
+
+service OrderService {` + tc.rpcs + `
+}
+
+message Order { string order_id = 1; }
+message GetOrderRequest { string order_id = 1; }
+`})
+		g, err := NewGenerator(tc.opts)
+		if err != nil {
+			t.Fatalf("failed to create generator: %v", err)
+		}
+		if err := g.Generate(plugin); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("expected error containing %q, got %v", tc.want, err)
+		}
+	}
+}
+
+const payloadProto = `
+syntax = "proto3";
+package shop.v1;
+
+import "metadata/v1/metadata.proto";
+
+service ShopService {
+  rpc GetShelf(GetShelfRequest) returns (GetShelfResponse) {}
+  rpc ListShelves(ListShelvesRequest) returns (ListShelvesResponse) {}
+  rpc SearchShelves(SearchShelvesRequest) returns (SearchShelvesResponse) {}
+  rpc CountShelves(CountShelvesRequest) returns (CountShelvesResponse) {}
+  rpc GetBook(GetBookRequest) returns (Book) {}
+  rpc GetLoanResponse(GetLoanResponseRequest) returns (LoanResponse) {}
+}
+
+message Shelf {
+  option (metadata.v1.entity) = true;
+  string shelf_id = 1 [(metadata.v1.key) = true];
+}
+
+message GetShelfRequest { string shelf_id = 1; }
+message GetShelfResponse { Shelf shelf = 1; }
+
+message ListShelvesRequest { string page_token = 1; }
+message ListShelvesResponse {
+  repeated Shelf shelves = 1 [(metadata.v1.payload) = true];
+  string next_page_token = 2;
+}
+
+message SearchShelvesRequest { string query = 1; }
+message SearchShelvesResponse { repeated Shelf shelves = 1; }
+
+message CountShelvesRequest { string query = 1; }
+message CountShelvesResponse { int32 count = 1; }
+
+// Domain messages and entities aren't unwrapped, even with a single field
+message Author { string name = 1; }
+message GetBookRequest { string book_id = 1; }
+message Book { Author author = 1; }
+
+message GetLoanResponseRequest { string loan_id = 1; }
+message LoanResponse {
+  option (metadata.v1.entity) = true;
+  option (metadata.v1.keys) = { fields: "shelf { shelf_id }" };
+  Shelf shelf = 1;
+}
+`
+
+func TestResponsePayload(t *testing.T) {
+	schema := runGenerator(t, Options{}, map[string]string{"shop/v1/shop.proto": payloadProto})["shop.v1.ShopService.graphql"]
+	for _, want := range []string{
+		"GetShelf(shelf_id: String): Shelf\n",
+		"ListShelves(page_token: String): [Shelf!]\n",
+		"SearchShelves(query: String): [Shelf!]\n",
+		"CountShelves(query: String): CountShelvesResponse\n",
+		"GetBook(book_id: String): Book\n",
+		"GetLoanResponse(loan_id: String): LoanResponse\n",
+	} {
+		if !strings.Contains(schema, want) {
+			t.Errorf("expected schema to contain %q\n%s", want, schema)
+		}
+	}
+
+	plugin := newTestPlugin(t, map[string]string{"shop/v1/shop.proto": payloadProto})
+	g, err := NewGenerator(Options{})
+	if err != nil {
+		t.Fatalf("failed to create generator: %v", err)
+	}
+	want := map[string]string{
+		"GetShelf":        "shelf",
+		"ListShelves":     "shelves",
+		"SearchShelves":   "shelves",
+		"CountShelves":    "",
+		"GetBook":         "",
+		"GetLoanResponse": "",
+	}
+	for _, m := range extractMethods(plugin.Files[len(plugin.Files)-1].Services[0], newTypeMapper(g)) {
+		if got := strings.Join(m.UnwrapPath, "."); got != want[m.ProtoName] {
+			t.Errorf("%s: got unwrap path %q, want %q", m.ProtoName, got, want[m.ProtoName])
+		}
+	}
+}
+
+const federationProto = `
+syntax = "proto3";
+package shop.v1;
+
+import "metadata/v1/metadata.proto";
+
+service OrderService {
+  rpc GetOrder(GetOrderRequest) returns (Order) {}
+  rpc LookupProduct(LookupProductRequest) returns (Product) {}
+}
+
+message GetOrderRequest { string order_id = 1; }
+message LookupProductRequest { string product_id = 1; }
+
+message Product {
+  option (metadata.v1.entity) = true;
+  option (metadata.v1.provides) = "LookupProduct";
+  option (metadata.v1.provides) = "shop.v1.OrderService.GetOrder";
+
+  string product_id = 1 [(metadata.v1.key) = true];
+  string name = 2 [(metadata.v1.external) = true];
+  double price = 3 [(metadata.v1.external) = true];
+}
+
+message Order {
+  option (metadata.v1.entity) = true;
+
+  string order_id = 1 [(metadata.v1.key) = true];
+  string product_id = 2 [(metadata.v1.external) = true];
+  Product product = 3 [
+    (metadata.v1.requires) = "product_id",
+    (metadata.v1.provides_fields) = "name"
+  ];
+  double total_price = 4 [(metadata.v1.computed_from) = "product { price }"];
+}
+`
+
+func TestFederationDirectives(t *testing.T) {
+	schema := runGenerator(t, Options{}, map[string]string{"shop/v1/shop.proto": federationProto})["shop.v1.OrderService.graphql"]
+	for _, want := range []string{
+		"  name: String! @external\n",
+		"  product_id: String! @external\n",
+		`  product: Product @requires(fields: "product_id") @provides(fields: "name")` + "\n",
+		`  total_price: Float! @computed(fields: "product { price }")` + "\n",
+		"directive @computed(fields: String!) on FIELD_DEFINITION\n",
+	} {
+		if !strings.Contains(schema, want) {
+			t.Errorf("expected schema to contain %q\n%s", want, schema)
+		}
+	}
+
+	// Field sets are rendered with the naming option
+	schema = runGenerator(t, Options{Naming: NamingLowerCamel}, map[string]string{"shop/v1/shop.proto": federationProto})["shop.v1.OrderService.graphql"]
+	for _, want := range []string{
+		`  product: Product @requires(fields: "productID") @provides(fields: "name")` + "\n",
+		`  totalPrice: Float! @computed(fields: "product { price }")` + "\n",
+	} {
+		if !strings.Contains(schema, want) {
+			t.Errorf("expected schema to contain %q\n%s", want, schema)
+		}
+	}
+
+	// and must select existing fields
+	for _, tc := range []struct{ from, to, want string }{
+		{`(metadata.v1.requires) = "product_id"`, `(metadata.v1.requires) = "productId"`, `invalid requires "productId" of shop.v1.Order.product: shop.v1.Order has no field "productId"`},
+		{`(metadata.v1.provides_fields) = "name"`, `(metadata.v1.provides_fields) = "title"`, `invalid provides_fields "title" of shop.v1.Order.product: shop.v1.Product has no field "title"`},
+		{`(metadata.v1.computed_from) = "product { price }"`, `(metadata.v1.computed_from) = "price"`, `invalid computed_from "price" of shop.v1.Order.total_price: shop.v1.Order has no field "price"`},
+		{`(metadata.v1.computed_from) = "product { price }"`, `(metadata.v1.computed_from) = "product"`, `field "product" of shop.v1.Order needs a selection of its fields`},
+		{`(metadata.v1.key) = true];
+  string product_id = 2`, `(metadata.v1.key) = true, (metadata.v1.provides_fields) = "name"];
+  string product_id = 2`, `invalid provides_fields "name" of shop.v1.Order.order_id: order_id has no fields to select`},
+	} {
+		plugin := newTestPlugin(t, map[string]string{"shop/v1/shop.proto": strings.Replace(federationProto, tc.from, tc.to, 1)})
+		g, err := NewGenerator(Options{})
+		if err != nil {
+			t.Fatalf("failed to create generator: %v", err)
+		}
+		if err := g.Generate(plugin); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("expected error containing %q, got %v", tc.want, err)
+		}
+	}
+
+	plugin := newTestPlugin(t, map[string]string{"shop/v1/shop.proto": federationProto})
+	g, err := NewGenerator(Options{})
+	if err != nil {
+		t.Fatalf("failed to create generator: %v", err)
+	}
+	file := plugin.Files[len(plugin.Files)-1]
+	data, err := prepareTemplateData(&schemaGroup{name: subgraphName(file.Services[0]), services: file.Services[:1], files: []*protogen.File{file}}, newTypeMapper(g))
+	if err != nil {
+		t.Fatalf("failed to prepare template data: %v", err)
+	}
+	for _, msg := range data.Messages {
+		if msg.Name != "Product" {
+			continue
+		}
+		var got []string
+		for _, m := range msg.ReferenceMethods {
+			got = append(got, m.ProtoName)
+		}
+		if strings.Join(got, ",") != "LookupProduct,GetOrder" {
+			t.Errorf("got reference methods %v, want [LookupProduct GetOrder]", got)
+		}
+	}
+}
+
+func TestEntityKeys(t *testing.T) {
+	const keysProto = `
+syntax = "proto3";
+package shop.v1;
+
+import "metadata/v1/metadata.proto";
+
+service InventoryService {
+  rpc GetItem(GetItemRequest) returns (Item) {}
+}
+
+message GetItemRequest { string sku = 1; }
+
+message Owner {
+  string user_id = 1;
+  string org_id = 2;
+}
+
+message Item {
+  option (metadata.v1.entity) = true;
+  option (metadata.v1.keys) = { fields: "upc" };
+  option (metadata.v1.keys) = { fields: "owner { user_id org_id }" };
+  option (metadata.v1.keys) = { fields: "legacy_id", resolvable: false };
+
+  string tenant_id = 1 [(metadata.v1.key) = true];
+  string sku = 2 [(metadata.v1.key) = true];
+  string upc = 3;
+  Owner owner = 4;
+  int64 legacy_id = 5;
+}
+`
+	tests := []struct {
+		name string
+		opts Options
+		want string
+	}{
+		{
+			name: "proto naming",
+			want: `type Item @key(fields: "tenant_id sku") @key(fields: "upc") @key(fields: "owner { user_id org_id }") @key(fields: "legacy_id", resolvable: false) {`,
+		},
+		{
+			name: "lowerCamel naming",
+			opts: Options{Naming: NamingLowerCamel},
+			want: `type Item @key(fields: "tenantID sku") @key(fields: "upc") @key(fields: "owner { userID orgID }") @key(fields: "legacyID", resolvable: false) {`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			schema := runGenerator(t, tc.opts, map[string]string{"shop/v1/shop.proto": keysProto})["shop.v1.InventoryService.graphql"]
+			if !strings.Contains(schema, tc.want) {
+				t.Errorf("expected schema to contain %q\n%s", tc.want, schema)
+			}
+		})
+	}
+
+	invalid := []struct {
+		name    string
+		message string
+		err     string
+	}{
+		{
+			name:    "unknown field",
+			message: `option (metadata.v1.keys) = { fields: "sku barcode" }; string sku = 1;`,
+			err:     `shop.v1.Item has no field "barcode"`,
+		},
+		{
+			name:    "unknown nested field",
+			message: `option (metadata.v1.keys) = { fields: "owner { id }" }; Owner owner = 1;`,
+			err:     `shop.v1.Owner has no field "id"`,
+		},
+		{
+			name:    "selection on a scalar",
+			message: `option (metadata.v1.keys) = { fields: "sku { id }" }; string sku = 1;`,
+			err:     `field "sku" of shop.v1.Item has no fields to select`,
+		},
+		{
+			name:    "object without selection",
+			message: `option (metadata.v1.keys) = { fields: "owner" }; Owner owner = 1;`,
+			err:     `field "owner" of shop.v1.Item needs a selection of its fields`,
+		},
+		{
+			name:    "unbalanced braces",
+			message: `option (metadata.v1.keys) = { fields: "owner { user_id" }; Owner owner = 1;`,
+			err:     "missing closing brace",
+		},
+		{
+			name:    "no key",
+			message: `string sku = 1;`,
+			err:     "entity shop.v1.Item has no key",
+		},
+		{
+			name:    "keys on a non-entity",
+			message: `message Tag { option (metadata.v1.keys) = { fields: "name" }; string name = 1; } string sku = 1 [(metadata.v1.key) = true]; Tag tag = 2;`,
+			err:     "shop.v1.Item.Tag declares keys but is not an entity",
+		},
+		{
+			name:    "key field on a non-entity",
+			message: `message Tag { string name = 1 [(metadata.v1.key) = true]; } string sku = 1 [(metadata.v1.key) = true]; Tag tag = 2;`,
+			err:     "shop.v1.Item.Tag declares keys but is not an entity",
+		},
+	}
+	for _, tc := range invalid {
+		t.Run(tc.name, func(t *testing.T) {
+			plugin := newTestPlugin(t, map[string]string{"shop/v1/shop.proto": `
+syntax = "proto3";
+package shop.v1;
+
+import "metadata/v1/metadata.proto";
+
+service InventoryService {
+  rpc GetItem(GetItemRequest) returns (Item) {}
+}
+
+message GetItemRequest { string sku = 1; }
+message Owner { string user_id = 1; }
+
+message Item {
+  option (metadata.v1.entity) = true;
+  ` + tc.message + `
+}
+`})
+			g, err := NewGenerator(Options{})
+			if err != nil {
+				t.Fatalf("failed to create generator: %v", err)
+			}
+			err = g.Generate(plugin)
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("expected error containing %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestFederationLink(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  string
+		contains []string
+	}{
+		{
+			name:    "no directives",
+			message: `string sku = 1;`,
+			contains: []string{
+				`@link(url: "https://specs.apollo.dev/federation/v2.0")` + "\n",
+			},
+		},
+		{
+			name:    "imports only the directives used",
+			message: `option (metadata.v1.entity) = true; string sku = 1 [(metadata.v1.key) = true];`,
+			contains: []string{
+				`@link(url: "https://specs.apollo.dev/federation/v2.0", import: ["@key"])`,
+			},
+		},
+		{
+			name: "field directives",
+			message: `
+  option (metadata.v1.entity) = true;
+  string sku = 1 [(metadata.v1.key) = true, (metadata.v1.shareable) = true];
+  string name = 2 [(metadata.v1.inaccessible) = true, (metadata.v1.tags) = "internal", (metadata.v1.tags) = "beta"];
+  double price = 3 [(metadata.v1.override_from) = "legacy", (metadata.v1.external) = true];`,
+			contains: []string{
+				`@link(url: "https://specs.apollo.dev/federation/v2.0", import: ["@key", "@external", "@shareable", "@inaccessible", "@override", "@tag"])`,
+				"  sku: String! @shareable\n",
+				`  name: String! @inaccessible @tag(name: "internal") @tag(name: "beta")` + "\n",
+				`  price: Float! @external @override(from: "legacy")` + "\n",
+			},
+		},
+		{
+			name: "type directives",
+			message: `
+  option (metadata.v1.entity) = true;
+  option (metadata.v1.type_shareable) = true;
+  option (metadata.v1.type_inaccessible) = true;
+  option (metadata.v1.type_tags) = "public";
+  option (metadata.v1.interface_object) = true;
+  string sku = 1 [(metadata.v1.key) = true];`,
+			contains: []string{
+				`@link(url: "https://specs.apollo.dev/federation/v2.3", import: ["@key", "@shareable", "@inaccessible", "@tag", "@interfaceObject"])`,
+				`type Item @key(fields: "sku") @interfaceObject @shareable @inaccessible @tag(name: "public") {`,
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			schema := runGenerator(t, Options{}, map[string]string{"shop/v1/shop.proto": `
+syntax = "proto3";
+package shop.v1;
+
+import "metadata/v1/metadata.proto";
+
+service InventoryService {
+  rpc GetItem(GetItemRequest) returns (Item) {}
+}
+
+message GetItemRequest { string sku = 1; }
+
+message Item {
+` + tc.message + `
+}
+`})["shop.v1.InventoryService.graphql"]
+			for _, want := range tc.contains {
+				if !strings.Contains(schema, want) {
+					t.Errorf("expected schema to contain %q\n%s", want, schema)
+				}
+			}
+		})
+	}
+}
+
+func TestServiceOptions(t *testing.T) {
+	const servicesProto = `
+syntax = "proto3";
+package shop.v1;
+
+import "metadata/v1/metadata.proto";
+
+service CatalogService {
+  option (metadata.v1.federated) = true;
+  option (metadata.v1.service_name) = "catalog";
+  rpc GetItem(GetItemRequest) returns (Item) {}
+}
+
+service AdminService {
+  option (metadata.v1.federated) = false;
+  rpc PurgeItem(GetItemRequest) returns (Item) {}
+}
+
+service LegacyService {
+  rpc LookupItem(GetItemRequest) returns (Item) {}
+}
+
+message GetItemRequest { string sku = 1; }
+
+message Item {
+  option (metadata.v1.entity) = true;
+  string sku = 1 [(metadata.v1.key) = true];
+  string name = 2 [(metadata.v1.external) = true];
+}
+`
+	tests := []struct {
+		name  string
+		opts  Options
+		files map[string][]string
+	}{
+		{
+			name: "non-federated services are plain schemas",
+			files: map[string][]string{
+				"catalog.graphql": {
+					"# Subgraph: catalog\n",
+					"extend schema\n  @link(",
+					"extend type Query {\n  GetItem(",
+					`type Item @key(fields: "sku") {`,
+				},
+				"shop.v1.AdminService.graphql": {
+					"type Query {\n  PurgeItem(",
+					"type Item {\n",
+					"  name: String!\n",
+				},
+				"shop.v1.LegacyService.graphql": {
+					"# Subgraph: shop.v1.LegacyService\n",
+					"extend type Query {\n  LookupItem(",
+				},
+			},
+		},
+		{
+			name: "federated_only skips services that don't opt in",
+			opts: Options{FederatedOnly: true},
+			files: map[string][]string{
+				"catalog.graphql": {"# Subgraph: catalog\n"},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			out := runGenerator(t, tc.opts, map[string]string{"shop/v1/shop.proto": servicesProto})
+			if len(out) != len(tc.files) {
+				t.Errorf("got %d files, want %d", len(out), len(tc.files))
+			}
+			for name, contains := range tc.files {
+				schema, ok := out[name]
+				if !ok {
+					t.Errorf("expected %s to be generated", name)
+					continue
+				}
+				for _, want := range contains {
+					if !strings.Contains(schema, want) {
+						t.Errorf("expected %s to contain %q\n%s", name, want, schema)
+					}
+				}
+			}
+			if schema := out["shop.v1.AdminService.graphql"]; strings.Contains(schema, "@") || strings.Contains(schema, "Subgraph") {
+				t.Errorf("expected plain schema without federation\n%s", schema)
+			}
+		})
+	}
+
+	plugin := newTestPlugin(t, map[string]string{"shop/v1/shop.proto": `
+syntax = "proto3";
+package shop.v1;
+
+import "metadata/v1/metadata.proto";
+
+service ItemService {
+  option (metadata.v1.service_name) = "items";
+  rpc GetItem(GetItemRequest) returns (GetItemRequest) {}
+}
+
+service ItemAdminService {
+  option (metadata.v1.service_name) = "items";
+  rpc PurgeItem(GetItemRequest) returns (GetItemRequest) {}
+}
+
+message GetItemRequest { string sku = 1; }
+`})
+	g, err := NewGenerator(Options{})
+	if err != nil {
+		t.Fatalf("failed to create generator: %v", err)
+	}
+	if err := g.Generate(plugin); err == nil || !strings.Contains(err.Error(), `both generate subgraph "items"`) {
+		t.Errorf("expected duplicate subgraph error, got %v", err)
+	}
+}
+
+func TestDeprecation(t *testing.T) {
+	schema := runGenerator(t, Options{}, map[string]string{"shop/v1/shop.proto": `
+syntax = "proto3";
+package shop.v1;
+
+import "google/api/field_behavior.proto";
+import "metadata/v1/metadata.proto";
+
+service ItemService {
+  rpc GetItem(GetItemRequest) returns (Item) {}
+  rpc CreateItem(CreateItemRequest) returns (Item) {}
+
+  // LookupItem finds an item.
+  // Deprecated: use GetItem.
+  rpc LookupItem(GetItemRequest) returns (Item) {
+    option deprecated = true;
+  }
+
+  rpc FindItem(GetItemRequest) returns (Item) {
+    option deprecated = true;
+    option (metadata.v1.operation_deprecation_reason) = "Use \"GetItem\" instead.";
+  }
+
+  rpc GetLegacyItem(GetItemRequest) returns (LegacyItem) {}
+  rpc ListOldItems(GetItemRequest) returns (OldItem) {}
+}
+
+message GetItemRequest { string sku = 1; }
+
+message CreateItemRequest {
+  Item item = 1;
+  string store = 2 [deprecated = true]; // Deprecated: every store stocks every item.
+  string region = 3 [deprecated = true, (google.api.field_behavior) = REQUIRED];
+}
+
+message Item {
+  option (metadata.v1.entity) = true;
+
+  string sku = 1 [(metadata.v1.key) = true];
+  string name = 2 [deprecated = true]; // Deprecated: use title.
+  string title = 3;
+  string color = 4 [deprecated = true];
+  string size = 5 [(metadata.v1.deprecation_reason) = "Sizes moved to variants."];
+  LegacyItem legacy = 6;
+  Status status = 7;
+}
+
+message LegacyItem {
+  option deprecated = true;
+  option (metadata.v1.type_deprecation_reason) = "Legacy items are read-only.";
+  string sku = 1;
+}
+
+// Deprecated: nothing returns old items any more.
+message OldItem {
+  option deprecated = true;
+  string sku = 1;
+}
+
+enum Status {
+  STATUS_UNSPECIFIED = 0;
+  STATUS_ACTIVE = 1;
+  STATUS_RETIRED = 2 [deprecated = true, (metadata.v1.value_deprecation_reason) = "Items are archived instead."];
+  STATUS_HIDDEN = 3 [deprecated = true];
+}
+`})["shop.v1.ItemService.graphql"]
+
+	for _, want := range []string{
+		"  GetItem(sku: String): Item\n",
+		`  LookupItem(sku: String): Item @deprecated(reason: "use GetItem.")` + "\n",
+		`  FindItem(sku: String): Item @deprecated(reason: "Use \"GetItem\" instead.")` + "\n",
+		`  GetLegacyItem(sku: String): LegacyItem @deprecated(reason: "Legacy items are read-only.")` + "\n",
+		`  ListOldItems(sku: String): OldItem @deprecated(reason: "nothing returns old items any more.")` + "\n",
+		`  name: String! @deprecated(reason: "use title.")` + "\n",
+		"  title: String!\n",
+		"  color: String! @deprecated\n",
+		`  size: String! @deprecated(reason: "Sizes moved to variants.")` + "\n",
+		`  legacy: LegacyItem @deprecated(reason: "Legacy items are read-only.")` + "\n",
+		// Arguments and input fields are deprecated unless they are
+		// required, which GraphQL doesn't allow
+		`  CreateItem(item: ItemInput, store: String @deprecated(reason: "every store stocks every item."), region: String!): Item` + "\n",
+		"input ItemInput {\n  sku: String\n" + `  name: String @deprecated(reason: "use title.")` + "\n  title: String\n  color: String @deprecated\n",
+		`  legacy: LegacyItemInput @deprecated(reason: "Legacy items are read-only.")` + "\n",
+		"  ACTIVE\n",
+		`  RETIRED @deprecated(reason: "Items are archived instead.")` + "\n",
+		"  HIDDEN @deprecated\n",
+		// Deprecation comments are only left in the descriptions of types,
+		// which can't be deprecated
+		"  \"\"\"\n  LookupItem finds an item.\n  \"\"\"\n  LookupItem(",
+		"\"\"\"\nDeprecated: nothing returns old items any more.\n\"\"\"\ntype OldItem {",
+	} {
+		if !strings.Contains(schema, want) {
+			t.Errorf("expected schema to contain %q\n%s", want, schema)
+		}
+	}
+	for _, unwanted := range []string{"Deprecated: use GetItem.", "Deprecated: use title."} {
+		if strings.Contains(schema, unwanted) {
+			t.Errorf("expected schema not to contain %q\n%s", unwanted, schema)
+		}
+	}
+}
+
+func TestDescriptions(t *testing.T) {
+	schema := runGenerator(t, Options{}, map[string]string{"shop/v1/shop.proto": `
+syntax = "proto3";
+package shop.v1;
+
+import "metadata/v1/metadata.proto";
+
+service ItemService {
+  // GetItem returns an item.
+  // See https://example.com/docs//items for details.
+  rpc GetItem(GetItemRequest) returns (Item) {}
+
+  // PurgeItem removes an item.
+  // @graphql-hide
+  // Only the on-call team may call this.
+  rpc PurgeItem(GetItemRequest) returns (Item) {}
+}
+
+message GetItemRequest {
+  // The item's SKU.
+  string sku = 1;
+}
+
+// Item is a thing for sale.
+//
+// Quote the name as """name""" in searches.
+message Item {
+  option (metadata.v1.entity) = true;
+
+  string sku = 1 [(metadata.v1.key) = true]; // The item's SKU.
+
+  // Display name,
+  //   indented example
+  string name = 2; // Localized.
+
+  // @graphql-hide Internal note.
+  string note = 3;
+
+  /* The item's price in cents. */
+  int32 price = 4;
+}
+`})["shop.v1.ItemService.graphql"]
+
+	for _, want := range []string{
+		"  \"\"\"\n  GetItem returns an item.\nSee https://example.com/docs//items for details.\n  \"\"\"\n  GetItem(",
+		"  \"\"\"\n  PurgeItem removes an item.\n  \"\"\"\n  PurgeItem(",
+		"\"\"\"\nItem is a thing for sale.\n\nQuote the name as \\\"\"\"name\\\"\"\" in searches.\n\"\"\"\ntype Item",
+		"  \"\"\"\n  The item's SKU.\n  \"\"\"\n  sku: String!",
+		"  \"\"\"\n  Display name,\n  indented example\n\nLocalized.\n  \"\"\"\n  name: String!",
+		"  \"\"\"\n  The item's price in cents.\n  \"\"\"\n  price: Int!",
+	} {
+		if !strings.Contains(schema, want) {
+			t.Errorf("expected schema to contain %q\n%s", want, schema)
+		}
+	}
+	for _, unwanted := range []string{"// ", "/*", "on-call", "Internal note", "@graphql-hide"} {
+		if strings.Contains(schema, unwanted) {
+			t.Errorf("expected schema not to contain %q\n%s", unwanted, schema)
+		}
+	}
+	if strings.Contains(schema, "\"\"\"\n  \"\"\"\n  note") {
+		t.Errorf("expected hidden comment to leave no empty description\n%s", schema)
+	}
+}
+
+func TestFieldBehavior(t *testing.T) {
+	schema := runGenerator(t, Options{}, map[string]string{"shop/v1/shop.proto": `
+syntax = "proto3";
+package shop.v1;
+
+import "google/api/field_behavior.proto";
+import "google/protobuf/timestamp.proto";
+import "metadata/v1/metadata.proto";
+
+service BookService {
+  rpc GetBook(GetBookRequest) returns (Book) {}
+  rpc CreateBook(CreateBookRequest) returns (Book) {}
+  rpc UpdateBook(UpdateBookRequest) returns (Book) {}
+}
+
+message GetBookRequest {
+  string name = 1 [(google.api.field_behavior) = REQUIRED];
+}
+
+message CreateBookRequest {
+  Book book = 1 [(google.api.field_behavior) = REQUIRED];
+  string book_id = 2;
+}
+
+message UpdateBookRequest {
+  Book book = 1 [(google.api.field_behavior) = REQUIRED];
+  google.protobuf.Timestamp valid_until = 2;
+}
+
+message Book {
+  option (metadata.v1.entity) = true;
+
+  string name = 1 [(metadata.v1.key) = true, (google.api.field_behavior) = IMMUTABLE];
+  string title = 2 [(google.api.field_behavior) = REQUIRED];
+  optional string subtitle = 3;
+  Author author = 4;
+  google.protobuf.Timestamp create_time = 5 [(google.api.field_behavior) = OUTPUT_ONLY];
+  string secret = 6 [(google.api.field_behavior) = INPUT_ONLY];
+  repeated string tags = 7;
+}
+
+message Author {
+  string author_id = 1 [(google.api.field_behavior) = IMMUTABLE];
+  string display_name = 2;
+}
+`})["shop.v1.BookService.graphql"]
+
+	tests := []struct {
+		name    string
+		snippet string
+		want    bool
+	}{
+		{
+			name:    "fields without presence are non-null outputs and INPUT_ONLY fields are left out",
+			snippet: "type Book @key(fields: \"name\") {\n  name: String!\n  title: String!\n  subtitle: String\n  author: Author\n  create_time: DateTime\n  tags: [String!]!\n}",
+			want:    true,
+		},
+		{
+			name:    "REQUIRED message fields are non-null inputs",
+			snippet: "CreateBook(book: BookInput!, book_id: String): Book",
+			want:    true,
+		},
+		{
+			name:    "OUTPUT_ONLY and INPUT_ONLY fields",
+			snippet: "input BookInput {\n  name: String\n  title: String!\n  subtitle: String\n  author: AuthorInput\n  secret: String\n  tags: [String!]\n}",
+			want:    true,
+		},
+		{
+			name:    "IMMUTABLE fields are left out of update inputs",
+			snippet: "input BookUpdateInput {\n  title: String!\n  subtitle: String\n  author: AuthorUpdateInput\n  secret: String\n  tags: [String!]\n}",
+			want:    true,
+		},
+		{
+			name:    "update methods take update inputs",
+			snippet: "UpdateBook(book: BookUpdateInput!, valid_until: DateTime): Book",
+			want:    true,
+		},
+		{
+			name:    "nested update inputs",
+			snippet: "input AuthorUpdateInput {\n  display_name: String\n}",
+			want:    true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := strings.Contains(schema, tc.snippet); got != tc.want {
+				t.Errorf("expected contains(%q) = %v, got %v\n%s", tc.snippet, tc.want, got, schema)
+			}
+		})
+	}
+}
+
+// pgvProto and protovalidateProto are the parts of the protoc-gen-validate
+// and protovalidate rule definitions the constraint tests use
+const pgvProto = `
+syntax = "proto2";
+package validate;
+
+import "google/protobuf/descriptor.proto";
+
+extend google.protobuf.FieldOptions {
+  optional FieldRules rules = 1071;
+}
+
+message FieldRules {
+  oneof type {
+    DoubleRules double = 2;
+    Int32Rules int32 = 3;
+    StringRules string = 14;
+    RepeatedRules repeated = 18;
+    MapRules map = 19;
+  }
+}
+
+message DoubleRules {
+  optional double lt = 2;
+  optional double lte = 3;
+  optional double gt = 4;
+  optional double gte = 5;
+}
+
+message Int32Rules {
+  optional int32 lt = 2;
+  optional int32 lte = 3;
+  optional int32 gt = 4;
+  optional int32 gte = 5;
+}
+
+message StringRules {
+  optional uint64 len = 19;
+  optional uint64 min_len = 2;
+  optional uint64 max_len = 3;
+  optional string pattern = 6;
+  optional string prefix = 7;
+  oneof well_known {
+    bool email = 12;
+    bool uri = 17;
+    bool uuid = 22;
+  }
+}
+
+message RepeatedRules {
+  optional uint64 min_items = 1;
+  optional uint64 max_items = 2;
+  optional bool unique = 3;
+}
+
+message MapRules {
+  optional uint64 min_pairs = 1;
+  optional uint64 max_pairs = 2;
+}
+`
+
+const protovalidateProto = `
+syntax = "proto2";
+package buf.validate;
+
+import "google/protobuf/descriptor.proto";
+
+extend google.protobuf.FieldOptions {
+  optional FieldRules field = 1159;
+}
+
+message FieldRules {
+  optional bool required = 25;
+  oneof type {
+    Int32Rules int32 = 3;
+    StringRules string = 14;
+  }
+}
+
+message Int32Rules {
+  oneof less_than {
+    int32 lt = 2;
+    int32 lte = 3;
+  }
+  oneof greater_than {
+    int32 gt = 4;
+    int32 gte = 5;
+  }
+}
+
+message StringRules {
+  optional uint64 min_len = 2;
+  optional uint64 max_len = 3;
+  oneof well_known {
+    bool email = 12;
+  }
+}
+`
+
+func TestConstraints(t *testing.T) {
+	out := runGenerator(t, Options{}, map[string]string{
+		"validate/validate.proto":     pgvProto,
+		"buf/validate/validate.proto": protovalidateProto,
+		"shop/v1/shop.proto": `
+syntax = "proto3";
+package shop.v1;
+
+import "validate/validate.proto";
+
+service UserService {
+  rpc CreateUser(CreateUserRequest) returns (User) {}
+}
+
+message CreateUserRequest {
+  string email = 1 [(validate.rules).string.email = true];
+  string handle = 2 [(validate.rules).string = {min_len: 3, max_len: 32, pattern: "^[a-z0-9_]+$"}];
+  int32 age = 3 [(validate.rules).int32 = {gte: 13, lt: 150}];
+  repeated string roles = 4 [(validate.rules).repeated = {min_items: 1, max_items: 5, unique: true}];
+  Profile profile = 5;
+  string code = 6 [(validate.rules).string.len = 6];
+  map<string, string> labels = 7 [(validate.rules).map.max_pairs = 10];
+  string nickname = 8;
+}
+
+message Profile {
+  double rating = 1 [(validate.rules).double = {gt: 0, lte: 5.5}];
+  string website = 2 [(validate.rules).string = {uri: true, prefix: "https://"}];
+}
+
+message User {
+  string email = 1;
+}
+`,
+		"shop/v2/shop.proto": `
+syntax = "proto3";
+package shop.v2;
+
+import "buf/validate/validate.proto";
+
+service UserService {
+  rpc CreateUser(CreateUserRequest) returns (User) {}
+}
+
+message CreateUserRequest {
+  string email = 1 [(buf.validate.field).string.email = true];
+  string handle = 2 [(buf.validate.field).string = {min_len: 3, max_len: 32}];
+  int32 age = 3 [(buf.validate.field).int32 = {gte: 13, lt: 150}];
+}
+
+message User {
+  string email = 1;
+}
+`,
+	})
+
+	tests := []struct {
+		file     string
+		contains []string
+	}{
+		{
+			file: "shop.v1.UserService.graphql",
+			contains: []string{
+				`email: String @constraint(format: "email")`,
+				`handle: String @constraint(minLength: 3, maxLength: 32, pattern: "^[a-z0-9_]+$")`,
+				`age: Int @constraint(min: 13, exclusiveMax: 150)`,
+				`roles: [String!] @constraint(minItems: 1, maxItems: 5)`,
+				`code: String @constraint(minLength: 6, maxLength: 6)`,
+				`labels: [CreateUserRequestLabelsEntryInput!] @constraint(maxItems: 10)`,
+				"nickname: String)",
+				"input ProfileInput {\n  rating: Float @constraint(max: 5.5, exclusiveMin: 0)\n" +
+					`  website: String @constraint(startsWith: "https://", format: "uri")` + "\n}",
+				"directive @constraint(minLength: Int, maxLength: Int",
+			},
+		},
+		{
+			file: "shop.v2.UserService.graphql",
+			contains: []string{
+				`email: String @constraint(format: "email")`,
+				`handle: String @constraint(minLength: 3, maxLength: 32)`,
+				`age: Int @constraint(min: 13, exclusiveMax: 150)`,
+				"directive @constraint(",
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.file, func(t *testing.T) {
+			schema := out[tc.file]
+			for _, want := range tc.contains {
+				if !strings.Contains(schema, want) {
+					t.Errorf("expected schema to contain %q\n%s", want, schema)
+				}
+			}
+		})
+	}
+
+	// Schemas without validation rules don't declare the directive
+	schema := runGenerator(t, Options{}, map[string]string{"shop/v1/shop.proto": payloadProto})["shop.v1.ShopService.graphql"]
+	if strings.Contains(schema, "@constraint") {
+		t.Errorf("expected no @constraint directive\n%s", schema)
+	}
+}
+
+const paginationProto = `
+syntax = "proto3";
+package shop.v1;
+
+import "metadata/v1/metadata.proto";
+
+service ShopService {
+  rpc ListShelves(ListShelvesRequest) returns (ListShelvesResponse) {}
+  rpc ListBooks(ListBooksRequest) returns (ListBooksResponse) {}
+  rpc ListShelfNames(ListShelfNamesRequest) returns (ListShelfNamesResponse) {}
+  rpc StreamShelves(StreamShelvesRequest) returns (stream ListShelvesResponse) {}
+}
+
+message Shelf {
+  option (metadata.v1.entity) = true;
+  string shelf_id = 1 [(metadata.v1.key) = true];
+}
+
+message Book { string title = 1; }
+
+message ListShelvesRequest {
+  int32 page_size = 1;
+  string page_token = 2;
+}
+message ListShelvesResponse {
+  repeated Shelf shelves = 1;
+  string next_page_token = 2;
+  int32 total_size = 3;
+}
+
+message ListBooksRequest {
+  string shelf_id = 1;
+  int32 page_size = 2;
+  string page_token = 3;
+}
+message ListBooksResponse {
+  repeated Book books = 1 [(metadata.v1.payload) = true];
+  repeated string unreachable = 2;
+  string next_page_token = 3;
+}
+
+message ListShelfNamesRequest { string page_token = 1; }
+message ListShelfNamesResponse {
+  repeated string names = 1;
+  string next_page_token = 2;
+}
+
+message StreamShelvesRequest {
+  int32 page_size = 1;
+  string page_token = 2;
+}
+`
+
+func TestPagination(t *testing.T) {
+	sources := map[string]string{"shop/v1/shop.proto": paginationProto}
+	schema := runGenerator(t, Options{}, sources)["shop.v1.ShopService.graphql"]
+	for _, want := range []string{
+		"ListShelves(first: Int, after: String): ShelfConnection\n",
+		"ListBooks(shelf_id: String, first: Int, after: String): BookConnection\n",
+		"ListShelfNames(page_token: String): ListShelfNamesResponse\n",
+		"StreamShelves(page_size: Int, page_token: String): ListShelvesResponse\n",
+		"type ShelfConnection @shareable {\n  edges: [ShelfEdge!]!\n  pageInfo: PageInfo!\n  totalCount: Int\n}",
+		"type ShelfEdge @shareable {\n  node: Shelf!\n  cursor: String\n}",
+		"type BookConnection @shareable {\n  edges: [BookEdge!]!\n  pageInfo: PageInfo!\n}",
+		"type PageInfo @shareable {\n  hasNextPage: Boolean!\n  hasPreviousPage: Boolean!\n  startCursor: String\n  endCursor: String\n}",
+		`import: ["@key", "@shareable"]`,
+	} {
+		if !strings.Contains(schema, want) {
+			t.Errorf("expected schema to contain %q\n%s", want, schema)
+		}
+	}
+
+	schema = runGenerator(t, Options{InputStyle: InputStyleObject}, sources)["shop.v1.ShopService.graphql"]
+	for _, want := range []string{
+		"ListShelves(first: Int, after: String): ShelfConnection\n",
+		"ListBooks(input: ListBooksRequestInput!, first: Int, after: String): BookConnection\n",
+		"input ListBooksRequestInput {\n  shelf_id: String\n}",
+	} {
+		if !strings.Contains(schema, want) {
+			t.Errorf("expected schema to contain %q\n%s", want, schema)
+		}
+	}
+
+	plugin := newTestPlugin(t, sources)
+	g, err := NewGenerator(Options{})
+	if err != nil {
+		t.Fatalf("failed to create generator: %v", err)
+	}
+	want := map[string]*Pagination{
+		"ListShelves": {PageSizeField: "page_size", PageTokenField: "page_token", ItemsField: "shelves", NextPageTokenField: "next_page_token", TotalSizeField: "total_size"},
+		"ListBooks":   {PageSizeField: "page_size", PageTokenField: "page_token", ItemsField: "books", NextPageTokenField: "next_page_token"},
+	}
+	for _, m := range extractMethods(plugin.Files[len(plugin.Files)-1].Services[0], newTypeMapper(g)) {
+		w, got := want[m.ProtoName], m.Pagination
+		if (w == nil) != (got == nil) {
+			t.Errorf("%s: got pagination %+v, want %+v", m.ProtoName, got, w)
+			continue
+		}
+		if got == nil {
+			continue
+		}
+		got.Connection, got.pageSize, got.pageToken, got.items, got.totalSize = nil, nil, nil, nil, nil
+		if *got != *w {
+			t.Errorf("%s: got pagination %+v, want %+v", m.ProtoName, got, w)
+		}
+	}
+}
+
+var typeResolutionProtos = map[string]string{
+	"common/v1/money.proto": `
+syntax = "proto3";
+package common.v1;
+
+message Money {
+  string currency_code = 1;
+  int64 units = 2;
+  Rounding rounding = 3;
+}
+
+enum Rounding {
+  ROUNDING_UNSPECIFIED = 0;
+  ROUNDING_HALF_UP = 1;
+}
+`,
+	"billing/v1/money.proto": `
+syntax = "proto3";
+package billing.v1;
+
+import "metadata/v1/metadata.proto";
+
+message Money {
+  option (metadata.v1.type_name) = "BillingAmount";
+  int64 cents = 1;
+}
+`,
+	"shop/v1/shop.proto": `
+syntax = "proto3";
+package shop.v1;
+
+import "billing/v1/money.proto";
+import "common/v1/money.proto";
+import "metadata/v1/metadata.proto";
+
+service ShopService {
+  rpc GetProduct(GetProductRequest) returns (Product) {}
+  rpc CreateProduct(CreateProductRequest) returns (Product) {}
+}
+
+message Product {
+  option (metadata.v1.entity) = true;
+  string sku = 1 [(metadata.v1.key) = true];
+  common.v1.Money price = 2;
+  Money discount = 3;
+  billing.v1.Money fee = 4;
+  repeated common.v1.Money history = 5;
+}
+
+message Money { double amount = 1; }
+
+message GetProductRequest { string sku = 1; }
+message CreateProductRequest { common.v1.Money price = 1; }
+`,
+}
+
+func TestTypeResolution(t *testing.T) {
+	schema := runGenerator(t, Options{}, typeResolutionProtos)["shop.v1.ShopService.graphql"]
+	for _, want := range []string{
+		"  price: CommonV1Money\n",
+		"  discount: Money\n",
+		"  fee: BillingAmount\n",
+		"  history: [CommonV1Money!]!\n",
+		"type CommonV1Money {\n  currency_code: String!\n  units: Int64!\n  rounding: Rounding!\n}",
+		"type Money {\n  amount: Float!\n}",
+		"type BillingAmount {\n  cents: Int64!\n}",
+		"enum Rounding {",
+		"CreateProduct(price: CommonV1MoneyInput): Product\n",
+		"input CommonV1MoneyInput {",
+	} {
+		if !strings.Contains(schema, want) {
+			t.Errorf("expected schema to contain %q\n%s", want, schema)
+		}
+	}
+	// Every type is declared once, however often it is referenced
+	for _, decl := range []string{"type CommonV1Money ", "type Product ", "enum Rounding "} {
+		if n := strings.Count(schema, decl); n != 1 {
+			t.Errorf("expected %q to be declared once, got %d\n%s", decl, n, schema)
+		}
+	}
+	// Request messages are only rendered as inputs
+	if strings.Contains(schema, "type CreateProductRequest") {
+		t.Errorf("expected request messages not to be rendered as types\n%s", schema)
+	}
+
+	plugin := newTestPlugin(t, typeResolutionProtos)
+	g, err := NewGenerator(Options{TypeCollisions: TypeCollisionsError})
+	if err != nil {
+		t.Fatalf("failed to create generator: %v", err)
+	}
+	err = g.Generate(plugin)
+	if want := `common.v1.Money and shop.v1.Money both generate GraphQL type "Money"`; err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("expected error containing %q, got %v", want, err)
+	}
+
+	if _, err := NewGenerator(Options{TypeCollisions: "rename"}); err == nil {
+		t.Error("expected an error for an invalid type_collisions option")
+	}
+}
+
+const generatedTypeNamesProto = `
+syntax = "proto3";
+package shop.v1;
+
+service PaymentService {
+  rpc GetPayment(GetPaymentRequest) returns (Payment) {}
+  rpc ListPayments(ListPaymentsRequest) returns (ListPaymentsResponse) {}
+}
+
+message Payment {
+  string payment_id = 1;
+  oneof method {
+    Card card = 2;
+    string voucher_code = 3;
+  }
+  map<string, string> labels = 4;
+  PaymentMethod preferred = 5;
+  PaymentVoucherCode last_voucher = 6;
+  PaymentLabelsEntry last_label = 7;
+  PageInfo page = 8;
+}
+
+message Card { string number = 1; }
+message PaymentMethod { string name = 1; }
+message PaymentVoucherCode { string code = 1; }
+message PaymentLabelsEntry { string key = 1; }
+message PageInfo { string token = 1; }
+
+message GetPaymentRequest { string payment_id = 1; }
+message ListPaymentsRequest {
+  int32 page_size = 1;
+  string page_token = 2;
+}
+message ListPaymentsResponse {
+  repeated Payment payments = 1;
+  string next_page_token = 2;
+}
+`
+
+func TestGeneratedTypeNames(t *testing.T) {
+	sources := map[string]string{"shop/v1/payment.proto": generatedTypeNamesProto}
+	schema := runGenerator(t, Options{}, sources)["shop.v1.PaymentService.graphql"]
+
+	// Messages keep their names, and the types generated for oneofs and
+	// maps are qualified instead
+	for _, want := range []string{
+		"  method: ShopV1PaymentMethod\n",
+		"union ShopV1PaymentMethod = Card | ShopV1PaymentVoucherCode\n",
+		"type ShopV1PaymentVoucherCode {\n  voucher_code: String!\n}",
+		"  labels: [ShopV1PaymentLabelsEntry!]!\n",
+		"type ShopV1PaymentLabelsEntry {\n  key: String!\n  value: String!\n}",
+		"  preferred: PaymentMethod\n",
+		"type PaymentMethod {\n  name: String!\n}",
+		"type PaymentVoucherCode {\n  code: String!\n}",
+		"type PaymentLabelsEntry {\n  key: String!\n}",
+		// PageInfo is declared for the connections, so the message is
+		// qualified
+		"  page: ShopV1PageInfo\n",
+		"type ShopV1PageInfo {\n  token: String!\n}",
+		"type PageInfo @shareable {\n  hasNextPage: Boolean!",
+	} {
+		if !strings.Contains(schema, want) {
+			t.Errorf("expected schema to contain %q\n%s", want, schema)
+		}
+	}
+
+	withoutPageInfo := strings.NewReplacer("  PageInfo page = 8;\n", "", "message PageInfo { string token = 1; }\n", "")
+	for _, tc := range []struct {
+		sources map[string]string
+		want    string
+	}{
+		{sources, `shop.v1.PageInfo generates GraphQL type "PageInfo", which is reserved for the page info of connections`},
+		{
+			map[string]string{"shop/v1/payment.proto": withoutPageInfo.Replace(generatedTypeNamesProto)},
+			`shop.v1.Payment.LabelsEntry generates GraphQL type "PaymentLabelsEntry", which is already taken`,
+		},
+	} {
+		g, err := NewGenerator(Options{TypeCollisions: TypeCollisionsError})
+		if err != nil {
+			t.Fatalf("failed to create generator: %v", err)
+		}
+		if err := g.Generate(newTestPlugin(t, tc.sources)); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("expected error containing %q, got %v", tc.want, err)
+		}
+	}
+}
+
+const streamingProto = `
+syntax = "proto3";
+package chat.v1;
+
+service ChatService {
+  rpc GetMessage(MessageRequest) returns (Message) {}
+  rpc WatchMessages(MessageRequest) returns (stream Message) {}
+  rpc UploadMessages(stream Message) returns (Message) {}
+  rpc Chat(stream Message) returns (stream Message) {}
+}
+
+message MessageRequest { string room_id = 1; }
+message Message { string text = 1; }
+`
+
+func TestStreaming(t *testing.T) {
+	sources := map[string]string{"chat/v1/chat.proto": streamingProto}
+	schema := runGenerator(t, Options{}, sources)["chat.v1.ChatService.graphql"]
+	for _, want := range []string{
+		"  subscription: Subscription\n",
+		"extend type Subscription {\n  WatchMessages(room_id: String): Message\n}",
+		"extend type Query {\n  GetMessage(room_id: String): Message\n}",
+	} {
+		if !strings.Contains(schema, want) {
+			t.Errorf("expected schema to contain %q\n%s", want, schema)
+		}
+	}
+	for _, skipped := range []string{"UploadMessages", "Chat("} {
+		if strings.Contains(schema, skipped) {
+			t.Errorf("expected client-streaming method %s to be skipped\n%s", skipped, schema)
+		}
+	}
+
+	plugin := newTestPlugin(t, sources)
+	g, err := NewGenerator(Options{Strict: true})
+	if err != nil {
+		t.Fatalf("failed to create generator: %v", err)
+	}
+	err = g.Generate(plugin)
+	if want := "chat.v1.ChatService.UploadMessages is a client-streaming method"; err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("expected error containing %q, got %v", want, err)
+	}
+}
+
+const resolversProto = `
+syntax = "proto3";
+package shop.v1;
+
+option go_package = "example.com/gen/shop/v1;shopv1";
+
+import "metadata/v1/metadata.proto";
+
+service ProductService {
+  option (metadata.v1.federated) = true;
+
+  rpc GetProduct(GetProductRequest) returns (Product) {}
+  rpc ListProducts(ListProductsRequest) returns (ListProductsResponse) {}
+  rpc CreateProduct(CreateProductRequest) returns (CreateProductResponse) {}
+  rpc WatchProducts(WatchProductsRequest) returns (stream Product) {}
+}
+
+message Product {
+  option (metadata.v1.entity) = true;
+  option (metadata.v1.provides) = "GetProduct";
+
+  string product_id = 1 [(metadata.v1.key) = true];
+  string name = 2;
+  int64 stock = 3;
+  optional double price = 4;
+  Status status = 5;
+  repeated string tags = 6;
+
+  enum Status {
+    STATUS_UNSPECIFIED = 0;
+    STATUS_ACTIVE = 1;
+  }
+}
+
+message GetProductRequest { string product_id = 1; }
+
+message ListProductsRequest {
+  int32 page_size = 1;
+  string page_token = 2;
+}
+message ListProductsResponse {
+  repeated Product products = 1;
+  string next_page_token = 2;
+  int32 total_size = 3;
+}
+
+message CreateProductRequest {
+  string name = 1;
+  optional uint32 stock = 2;
+}
+message CreateProductResponse { Product product = 1; }
+
+message WatchProductsRequest { Product.Status status = 1; }
+`
+
+// resolversModels are the gqlgen models and resolver interfaces gqlgen
+// generates for the schema of resolversProto
+var resolversModels = map[string]string{
+	"gateway/graph/model/models_gen.go": `package model
+
+type Product struct {
+	ProductID string
+	Name      string
+	Stock     int64
+	Price     *float64
+	Status    ProductStatus
+	Tags      []string
+}
+
+func (Product) IsEntity() {}
+
+type ProductConnection struct {
+	Edges      []*ProductEdge
+	PageInfo   *PageInfo
+	TotalCount *int
+}
+
+type ProductEdge struct {
+	Node   *Product
+	Cursor *string
+}
+
+type PageInfo struct {
+	HasNextPage     bool
+	HasPreviousPage bool
+	StartCursor     *string
+	EndCursor       *string
+}
+
+type ProductStatus string
+
+const (
+	ProductStatusUnspecified ProductStatus = "UNSPECIFIED"
+	ProductStatusActive      ProductStatus = "ACTIVE"
+)
+`,
+	"gateway/graph/generated.go": `package graph
+
+import (
+	"context"
+
+	"example.com/gateway/graph/model"
+)
+
+type QueryResolver interface {
+	GetProduct(ctx context.Context, productID *string) (*model.Product, error)
+	ListProducts(ctx context.Context, first *int, after *string) (*model.ProductConnection, error)
+}
+
+type MutationResolver interface {
+	CreateProduct(ctx context.Context, name *string, stock *int64) (*model.Product, error)
+}
+
+type SubscriptionResolver interface {
+	WatchProducts(ctx context.Context, status *model.ProductStatus) (<-chan *model.Product, error)
+}
+
+type EntityResolver interface {
+	FindProductByProductID(ctx context.Context, productID string) (*model.Product, error)
+}
+`,
+}
+
+func TestResolvers(t *testing.T) {
+	sources := map[string]string{"shop/v1/shop.proto": resolversProto}
+	opts := Options{ResolverPackage: "example.com/gateway/graph"}
+	files := runPlugin(t, opts, sources, (*Generator).GenerateResolvers)
+
+	for name, want := range map[string][]string{
+		"resolver.go": {
+			"ProductService shopv1connect.ProductServiceClient\n",
+			"func NewResolver(productService shopv1connect.ProductServiceClient) *Resolver {",
+			"func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }",
+			"func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }",
+			"func (r *Resolver) Subscription() SubscriptionResolver { return &subscriptionResolver{r} }",
+			"func (r *Resolver) Entity() EntityResolver { return &entityResolver{r} }",
+		},
+		"shop.v1.ProductService.resolvers.go": {
+			"func (r *queryResolver) GetProduct(ctx context.Context, productID *string) (*model.Product, error) {",
+			"req := &v1.GetProductRequest{}\n\tif productID != nil {\n\t\treq.ProductId = *productID\n\t}\n",
+			"resp, err := r.ProductService.GetProduct(ctx, connect.NewRequest(req))",
+			"return ProductFromProto(msg), nil",
+			"func (r *queryResolver) ListProducts(ctx context.Context, first *int, after *string) (*model.ProductConnection, error) {",
+			"if first != nil {\n\t\treq.PageSize = int32(*first)\n\t}",
+			"edge := &model.ProductEdge{Node: ProductFromProto(v)}",
+			"out.TotalCount = ptr(int(msg.GetTotalSize()))",
+			"if i == len(msg.Products)-1 && msg.NextPageToken != \"\" {\n\t\t\tedge.Cursor = ptr(msg.NextPageToken)\n\t\t}",
+			"func (r *mutationResolver) CreateProduct(ctx context.Context, name *string, stock *int64) (*model.Product, error) {",
+			"if stock != nil {\n\t\treq.Stock = ptr(uint32(*stock))\n\t}",
+			"out = ProductFromProto(msg.GetProduct())",
+			"func (r *subscriptionResolver) WatchProducts(ctx context.Context, status *model.ProductStatus) (<-chan *model.Product, error) {",
+			"for stream.Receive() {",
+		},
+		"entity.resolvers.go": {
+			"func (r *entityResolver) FindProductByProductID(ctx context.Context, productID string) (*model.Product, error) {",
+			"resp, err := r.ProductService.GetProduct(ctx, connect.NewRequest(req))",
+		},
+		"convert.go": {
+			"func ProductFromProto(in *v1.Product) *model.Product {",
+			"out.Stock = in.GetStock()",
+			"if in.Price != nil {\n\t\tout.Price = ptr(in.GetPrice())\n\t}",
+			"out.Status = ProductStatusFromProto(in.GetStatus())",
+			"case v1.Product_STATUS_ACTIVE:\n\t\treturn model.ProductStatusActive",
+			"func ProductStatusToProto(in model.ProductStatus) v1.Product_Status {",
+		},
+		gqlgenModelsFile: {
+			"  Int64:\n    model:\n      - example.com/gateway/graph.Int64\n",
+		},
+		gqlgenSupportFile: {
+			"func MarshalInt64(v int64) graphql.Marshaler {",
+		},
+	} {
+		src, ok := files[name]
+		if !ok {
+			t.Errorf("expected %s to be generated, got %v", name, files)
+			continue
+		}
+		for _, w := range want {
+			if !strings.Contains(src, w) {
+				t.Errorf("expected %s to contain %q\n%s", name, w, src)
+			}
+		}
+	}
+
+	// The resolvers implement gqlgen's resolver interfaces with its models
+	module := make(map[string]string)
+	for name, src := range resolversModels {
+		module[name] = src
+	}
+	for name, src := range files {
+		if strings.HasSuffix(name, ".go") {
+			module["gateway/graph/"+name] = src
+		}
+	}
+	vetGoModule(t, sources, module)
+
+	// With the gateway's gqlgen.yml bindings, Int is an int32 and Int64 an int
+	opts.ScalarModels = []string{
+		"Int:github.com/99designs/gqlgen/graphql.Int32",
+		"Int64:github.com/99designs/gqlgen/graphql.Int",
+	}
+	files = runPlugin(t, opts, sources, (*Generator).GenerateResolvers)
+	resolvers := files["shop.v1.ProductService.resolvers.go"]
+	for _, w := range []string{
+		"ListProducts(ctx context.Context, first *int32, after *string)",
+		"out.TotalCount = ptr(msg.GetTotalSize())",
+		"CreateProduct(ctx context.Context, name *string, stock *int)",
+	} {
+		if !strings.Contains(resolvers, w) {
+			t.Errorf("expected the resolvers to contain %q\n%s", w, resolvers)
+		}
+	}
+	if models := files[gqlgenModelsFile]; strings.Contains(models, "Int64:") {
+		t.Errorf("expected Int64 to be left to gqlgen.yml\n%s", models)
+	}
+	bound := strings.NewReplacer("*int64", "*int", "*int", "*int32", "Stock     int64", "Stock     int")
+	module = make(map[string]string)
+	for name, src := range resolversModels {
+		module[name] = bound.Replace(src)
+	}
+	for name, src := range files {
+		if strings.HasSuffix(name, ".go") {
+			module["gateway/graph/"+name] = src
+		}
+	}
+	vetGoModule(t, sources, module)
+
+	plugin := newTestPlugin(t, sources)
+	g, err := NewGenerator(Options{})
+	if err != nil {
+		t.Fatalf("failed to create generator: %v", err)
+	}
+	if err := g.GenerateResolvers(plugin); err == nil || !strings.Contains(err.Error(), "resolver_package is required") {
+		t.Errorf("expected missing resolver_package error, got %v", err)
+	}
+	for _, model := range []string{"Int", "Int:graphql.Int32", "Int:github.com/99designs/gqlgen/graphql.Uint128"} {
+		if _, err := NewGenerator(Options{ScalarModels: []string{model}}); err == nil {
+			t.Errorf("expected an invalid scalar_model error for %q", model)
+		}
+	}
+}
+
+const conversionsProto = `
+syntax = "proto3";
+package shop.v1;
+
+option go_package = "example.com/gen/shop/v1;shopv1";
+
+import "google/protobuf/any.proto";
+import "google/protobuf/duration.proto";
+import "google/protobuf/empty.proto";
+import "google/protobuf/field_mask.proto";
+import "google/protobuf/struct.proto";
+import "google/protobuf/timestamp.proto";
+import "google/protobuf/wrappers.proto";
+
+service CatalogService {
+  rpc GetItem(GetItemRequest) returns (Item) {}
+  rpc UpdateItem(UpdateItemRequest) returns (Item) {}
+}
+
+message GetItemRequest { string item_id = 1; }
+message UpdateItemRequest {
+  Item item = 1;
+  google.protobuf.FieldMask update_mask = 2;
+}
+
+message Item {
+  string item_id = 1;
+  bytes thumbnail = 2;
+  google.protobuf.Timestamp created_at = 3;
+  google.protobuf.Duration ttl = 4;
+  google.protobuf.Struct attributes = 5;
+  google.protobuf.Value extra = 6;
+  google.protobuf.Any details = 7;
+  google.protobuf.StringValue nickname = 8;
+  google.protobuf.Empty archived = 9;
+  repeated google.protobuf.Timestamp restocks = 10;
+  map<string, int32> stock = 11;
+  map<string, Price> prices = 12;
+  oneof pricing {
+    Price fixed = 13;
+    string formula = 14;
+    google.protobuf.Timestamp free_until = 15;
+  }
+  repeated Price history = 16;
+  Color color = 17;
+  optional float rating = 18;
+  uint32 views = 19;
+}
+
+message Price {
+  int64 cents = 1;
+  string currency = 2;
+}
+
+enum Color {
+  COLOR_UNSPECIFIED = 0;
+  COLOR_RED = 1;
+}
+`
+
+// conversionsModels are the gqlgen models and resolver interfaces gqlgen
+// generates for the schema of conversionsProto, with the scalars bound to
+// the models the resolver plugin generates
+var conversionsModels = map[string]string{
+	"gateway/graph/model/models_gen.go": `package model
+
+import (
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type ItemPricing interface {
+	IsItemPricing()
+}
+
+type Item struct {
+	ItemID     string
+	Thumbnail  []byte
+	CreatedAt  *timestamppb.Timestamp
+	TTL        *durationpb.Duration
+	Attributes *structpb.Value
+	Extra      *structpb.Value
+	Details    *structpb.Value
+	Nickname   *string
+	Archived   *bool
+	Restocks   []*timestamppb.Timestamp
+	Stock      []*ItemStockEntry
+	Prices     []*ItemPricesEntry
+	Pricing    ItemPricing
+	History    []*Price
+	Color      Color
+	Rating     *float64
+	Views      int64
+}
+
+type ItemInput struct {
+	ItemID     *string
+	Thumbnail  []byte
+	CreatedAt  *timestamppb.Timestamp
+	TTL        *durationpb.Duration
+	Attributes *structpb.Value
+	Extra      *structpb.Value
+	Details    *structpb.Value
+	Nickname   *string
+	Archived   *bool
+	Restocks   []*timestamppb.Timestamp
+	Stock      []*ItemStockEntryInput
+	Prices     []*ItemPricesEntryInput
+	Pricing    *ItemPricingInput
+	History    []*PriceInput
+	Color      *Color
+	Rating     *float64
+	Views      *int64
+}
+
+type Price struct {
+	Cents    int64
+	Currency string
+}
+
+func (Price) IsItemPricing() {}
+
+type PriceInput struct {
+	Cents    *int64
+	Currency *string
+}
+
+type ItemStockEntry struct {
+	Key   string
+	Value int
+}
+
+type ItemStockEntryInput struct {
+	Key   string
+	Value int
+}
+
+type ItemPricesEntry struct {
+	Key   string
+	Value *Price
+}
+
+type ItemPricesEntryInput struct {
+	Key   string
+	Value *PriceInput
+}
+
+type ItemFormula struct {
+	Formula string
+}
+
+func (ItemFormula) IsItemPricing() {}
+
+type ItemFreeUntil struct {
+	FreeUntil *timestamppb.Timestamp
+}
+
+func (ItemFreeUntil) IsItemPricing() {}
+
+type ItemPricingInput struct {
+	Fixed     *PriceInput
+	Formula   *string
+	FreeUntil *timestamppb.Timestamp
+}
+
+type Color string
+
+const (
+	ColorUnspecified Color = "UNSPECIFIED"
+	ColorRed         Color = "RED"
+)
+`,
+	"gateway/graph/generated.go": `package graph
+
+import (
+	"context"
+
+	"example.com/gateway/graph/model"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+type QueryResolver interface {
+	GetItem(ctx context.Context, itemID *string) (*model.Item, error)
+}
+
+type MutationResolver interface {
+	UpdateItem(ctx context.Context, item *model.ItemInput, updateMask *fieldmaskpb.FieldMask) (*model.Item, error)
+}
+`,
+	// Converts an item to its object and back through the input it would
+	// be sent as
+	"roundtrip/main.go": `package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"example.com/gateway/graph"
+	"example.com/gateway/graph/model"
+	shopv1 "example.com/gen/shop/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func main() {
+	attributes, _ := structpb.NewStruct(map[string]any{"size": "L", "tags": []any{"new"}})
+	details, _ := anypb.New(&shopv1.Price{Cents: 250, Currency: "EUR"})
+	item := &shopv1.Item{
+		ItemId:     "item-1",
+		Thumbnail:  []byte{0x89, 0x50},
+		CreatedAt:  timestamppb.New(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)),
+		Ttl:        durationpb.New(90 * time.Second),
+		Attributes: attributes,
+		Extra:      structpb.NewNumberValue(1.5),
+		Details:    details,
+		Nickname:   wrapperspb.String("widget"),
+		Archived:   &emptypb.Empty{},
+		Restocks:   []*timestamppb.Timestamp{timestamppb.New(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))},
+		Stock:      map[string]int32{"a": 3, "b": 5},
+		Prices:     map[string]*shopv1.Price{"eu": {Cents: 1999, Currency: "EUR"}},
+		Pricing:    &shopv1.Item_Fixed{Fixed: &shopv1.Price{Cents: 1500, Currency: "USD"}},
+		History:    []*shopv1.Price{{Cents: 1200, Currency: "USD"}},
+		Color:      shopv1.Color_COLOR_RED,
+		Rating:     proto.Float32(4.5),
+		Views:      1 << 31,
+	}
+
+	back := graph.ItemInputToProto(itemInput(graph.ItemFromProto(item)))
+	if !proto.Equal(item, back) {
+		fmt.Printf("round trip changed the item:\n%v\n%v\n", item, back)
+		os.Exit(1)
+	}
+}
+
+// itemInput copies an Item object to the ItemInput it would be sent as
+func itemInput(in *model.Item) *model.ItemInput {
+	out := &model.ItemInput{
+		ItemID:     &in.ItemID,
+		Thumbnail:  in.Thumbnail,
+		CreatedAt:  in.CreatedAt,
+		TTL:        in.TTL,
+		Attributes: in.Attributes,
+		Extra:      in.Extra,
+		Details:    in.Details,
+		Nickname:   in.Nickname,
+		Archived:   in.Archived,
+		Restocks:   in.Restocks,
+		Color:      &in.Color,
+		Rating:     in.Rating,
+		Views:      &in.Views,
+	}
+	for _, e := range in.Stock {
+		out.Stock = append(out.Stock, &model.ItemStockEntryInput{Key: e.Key, Value: e.Value})
+	}
+	for _, e := range in.Prices {
+		out.Prices = append(out.Prices, &model.ItemPricesEntryInput{Key: e.Key, Value: priceInput(e.Value)})
+	}
+	for _, p := range in.History {
+		out.History = append(out.History, priceInput(p))
+	}
+	switch p := in.Pricing.(type) {
+	case *model.Price:
+		out.Pricing = &model.ItemPricingInput{Fixed: priceInput(p)}
+	case *model.ItemFormula:
+		out.Pricing = &model.ItemPricingInput{Formula: &p.Formula}
+	case *model.ItemFreeUntil:
+		out.Pricing = &model.ItemPricingInput{FreeUntil: p.FreeUntil}
+	}
+	return out
+}
+
+func priceInput(in *model.Price) *model.PriceInput {
+	return &model.PriceInput{Cents: &in.Cents, Currency: &in.Currency}
+}
+`,
+}
+
+func TestConversions(t *testing.T) {
+	sources := map[string]string{"shop/v1/shop.proto": conversionsProto}
+	files := runPlugin(t, Options{ResolverPackage: "example.com/gateway/graph"}, sources, (*Generator).GenerateResolvers)
+	src := files["convert.go"]
+	for _, want := range []string{
+		// Objects, with nil-safe message fields
+		"func ItemFromProto(in *v1.Item) *model.Item {\n\tif in == nil {\n\t\treturn nil\n\t}",
+		"\tout.Thumbnail = in.GetThumbnail()\n",
+		"\tout.CreatedAt = in.GetCreatedAt()\n",
+		"\tout.TTL = in.GetTtl()\n",
+		"\tout.Attributes = JSONFromStruct(in.GetAttributes())\n",
+		"\tout.Extra = in.GetExtra()\n",
+		"\tout.Details = JSONFromAny(in.GetDetails())\n",
+		"\tif in.Nickname != nil {\n\t\tout.Nickname = ptr(in.Nickname.GetValue())\n\t}\n",
+		"\tif in.Archived != nil {\n\t\tout.Archived = ptr(in.Archived != nil)\n\t}\n",
+		"\tif in.Rating != nil {\n\t\tout.Rating = ptr(float64(in.GetRating()))\n\t}\n",
+		"\tout.Views = int64(in.GetViews())\n",
+		"\tout.Color = ColorFromProto(in.GetColor())\n",
+		// Repeated and map fields
+		"\tfor _, v := range in.GetRestocks() {\n\t\tout.Restocks = append(out.Restocks, v)\n\t}\n",
+		"\tfor _, v := range in.GetHistory() {\n\t\tout.History = append(out.History, PriceFromProto(v))\n\t}\n",
+		"\tfor _, k := range slices.Sorted(maps.Keys(in.GetStock())) {\n\t\tv := in.GetStock()[k]\n\t\tout.Stock = append(out.Stock, &model.ItemStockEntry{Key: k, Value: int(v)})\n\t}\n",
+		"out.Prices = append(out.Prices, &model.ItemPricesEntry{Key: k, Value: PriceFromProto(v)})",
+		// Oneof unions
+		"\tswitch v := in.GetPricing().(type) {\n\tcase *v1.Item_Fixed:\n\t\tif v.Fixed != nil {\n\t\t\tout.Pricing = PriceFromProto(v.Fixed)\n\t\t}\n",
+		"\tcase *v1.Item_Formula:\n\t\tout.Pricing = &model.ItemFormula{Formula: v.Formula}\n",
+		"\t\t\tout.Pricing = &model.ItemFreeUntil{FreeUntil: v.FreeUntil}\n",
+		// Inputs
+		"func ItemInputToProto(in *model.ItemInput) *v1.Item {",
+		"\tout.CreatedAt = in.CreatedAt\n",
+		"\tout.Attributes = JSONToStruct(in.Attributes)\n",
+		"\tif in.Nickname != nil {\n\t\tout.Nickname = wrapperspb.String(*in.Nickname)\n\t}\n",
+		"\tif in.Archived != nil {\n\t\tout.Archived = emptyToProto(*in.Archived)\n\t}\n",
+		"\tif in.Rating != nil {\n\t\tout.Rating = ptr(float32(*in.Rating))\n\t}\n",
+		"\tif in.Views != nil {\n\t\tout.Views = uint32(*in.Views)\n\t}\n",
+		"\tfor _, e := range in.Prices {\n\t\tif out.Prices == nil {\n\t\t\tout.Prices = make(map[string]*v1.Price, len(in.Prices))\n\t\t}\n\t\tout.Prices[e.Key] = PriceInputToProto(e.Value)\n\t}\n",
+		"\tswitch {\n\tcase in.Pricing == nil:\n\tcase in.Pricing.Fixed != nil:\n\t\tout.Pricing = &v1.Item_Fixed{Fixed: PriceInputToProto(in.Pricing.Fixed)}\n",
+		"\tcase in.Pricing.Formula != nil:\n\t\tout.Pricing = &v1.Item_Formula{Formula: *in.Pricing.Formula}\n",
+		// Enums and well-known types
+		"func ColorToProto(in model.Color) v1.Color {",
+		"func JSONFromStruct(in *structpb.Struct) *structpb.Value {",
+		"func JSONToAny(in *structpb.Value) *anypb.Any {",
+		"func convertProtoJSON(in, out proto.Message) bool {",
+		"func emptyToProto(in bool) *emptypb.Empty {",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("expected convert.go to contain %q\n%s", want, src)
+		}
+	}
+
+	// The scalars are bound to the Go types the conversions use
+	models := files[gqlgenModelsFile]
+	for _, want := range []string{
+		"  DateTime:\n    model:\n      - example.com/gateway/graph.Timestamp\n",
+		"  JSON:\n    model:\n      - example.com/gateway/graph.Value\n",
+		"  Base64:\n    model:\n      - example.com/gateway/graph.Bytes\n",
+	} {
+		if !strings.Contains(models, want) {
+			t.Errorf("expected %s to contain %q\n%s", gqlgenModelsFile, want, models)
+		}
+	}
+
+	module := make(map[string]string)
+	for name, src := range conversionsModels {
+		module[name] = src
+	}
+	for name, src := range files {
+		if strings.HasSuffix(name, ".go") {
+			module["gateway/graph/"+name] = src
+		}
+	}
+	dir := vetGoModule(t, sources, module)
+	runGo(t, dir, "run", "./roundtrip")
+
+	// Enums whose zero value is dropped are null when zero
+	opts := Options{ResolverPackage: "example.com/gateway/graph", EnumDropUnspecified: true}
+	files = runPlugin(t, opts, sources, (*Generator).GenerateResolvers)
+	src = files["convert.go"]
+	for _, want := range []string{
+		"\tif in.GetColor() != 0 {\n\t\tout.Color = ptr(ColorFromProto(in.GetColor()))\n\t}\n",
+		"\tif in.Color != nil {\n\t\tout.Color = ColorToProto(*in.Color)\n\t}\n",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("expected convert.go to contain %q\n%s", want, src)
+		}
+	}
+	nullable := strings.NewReplacer(
+		"\tColor      Color\n", "\tColor      *Color\n",
+		"\t\tColor:      shopv1.Color_COLOR_RED,\n", "",
+		"\t\tColor:      &in.Color,\n", "\t\tColor:      in.Color,\n",
+	)
+	module = make(map[string]string)
+	for name, src := range conversionsModels {
+		module[name] = nullable.Replace(src)
+	}
+	for name, src := range files {
+		if strings.HasSuffix(name, ".go") {
+			module["gateway/graph/"+name] = src
+		}
+	}
+	dir = vetGoModule(t, sources, module)
+	runGo(t, dir, "run", "./roundtrip")
+}
+
+func TestGqlgenModels(t *testing.T) {
+	sources := map[string]string{"shop/v1/shop.proto": conversionsProto}
+	opts := Options{GqlgenModels: true, GqlgenPackage: "example.com/gateway/graph/schema"}
+	out := runGenerator(t, opts, sources)
+
+	models := out[gqlgenModelsFile]
+	for _, want := range []string{
+		// Objects and inputs, with resolvers for fields gqlgen can't bind,
+		// including builtin scalars gqlgen has no model for
+		"  Item:\n    model:\n      - example.com/gen/shop/v1.Item\n    fields:\n      nickname:\n        resolver: true\n      archived:\n        resolver: true\n      stock:\n        resolver: true\n      prices:\n        resolver: true\n      pricing:\n        resolver: true\n      rating:\n        resolver: true\n",
+		"  Price:\n    model:\n      - example.com/gen/shop/v1.Price\n",
+		"  ItemInput:\n    model:\n      - example.com/gen/shop/v1.Item\n",
+		"  PriceInput:\n    model:\n      - example.com/gen/shop/v1.Price\n",
+		// Enums, unions and scalars
+		"  Color:\n    model:\n      - example.com/gen/shop/v1.Color\n    enum_values:\n      UNSPECIFIED:\n        value: example.com/gen/shop/v1.Color_COLOR_UNSPECIFIED\n      RED:\n        value: example.com/gen/shop/v1.Color_COLOR_RED\n",
+		"  ItemPricing:\n    model:\n      - example.com/gateway/graph/schema.ItemPricing\n",
+		"  DateTime:\n    model:\n      - example.com/gateway/graph/schema.Timestamp\n",
+		"  Int64:\n    model:\n      - example.com/gateway/graph/schema.Int64\n      - example.com/gateway/graph/schema.Uint32\n",
+		"  JSON:\n    model:\n      - example.com/gateway/graph/schema.Value\n      - example.com/gateway/graph/schema.Struct\n      - example.com/gateway/graph/schema.Any\n",
+	} {
+		if !strings.Contains(models, want) {
+			t.Errorf("expected %s to contain %q\n%s", gqlgenModelsFile, want, models)
+		}
+	}
+	// The builtin scalars keep the models gqlgen.yml binds them to
+	for _, builtin := range []string{"Int", "Float", "String", "Boolean", "ID"} {
+		if strings.Contains(models, "  "+builtin+":\n") {
+			t.Errorf("expected %s to be left to gqlgen.yml\n%s", builtin, models)
+		}
+	}
+
+	// Enums whose zero value is dropped can't be bound to the proto enum,
+	// which has no null
+	dropped := runGenerator(t, Options{GqlgenModels: true, GqlgenPackage: opts.GqlgenPackage, EnumDropUnspecified: true}, sources)[gqlgenModelsFile]
+	for _, want := range []string{
+		"      pricing:\n        resolver: true\n      color:\n        resolver: true\n",
+		"    enum_values:\n      RED:\n        value: example.com/gen/shop/v1.Color_COLOR_RED\n",
+	} {
+		if !strings.Contains(dropped, want) {
+			t.Errorf("expected %s to contain %q\n%s", gqlgenModelsFile, want, dropped)
+		}
+	}
+
+	src := out[gqlgenSupportFile]
+	dir := vetGoModule(t, sources, map[string]string{
+		"gateway/graph/schema/" + gqlgenSupportFile: src,
+		// uint32 values don't fit in an Int, so they are Int64 strings
+		"uint32/main.go": `package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"example.com/gateway/graph/schema"
+)
+
+func main() {
+	var b strings.Builder
+	schema.MarshalUint32(1 << 31).MarshalGQL(&b)
+	fromString, err := schema.UnmarshalUint32("2147483648")
+	fromInt, err2 := schema.UnmarshalUint32(int64(1 << 31))
+	_, overflow := schema.UnmarshalUint32(int64(1 << 32))
+	if b.String() != ` + "`" + `"2147483648"` + "`" + ` || fromString != 1<<31 || err != nil || fromInt != 1<<31 || err2 != nil || overflow == nil {
+		fmt.Println(b.String(), fromString, err, fromInt, err2, overflow)
+		os.Exit(1)
+	}
+}
+`,
+	})
+	runGo(t, dir, "run", "./uint32")
+	for _, want := range []string{
+		"package schema\n",
+		"type ItemPricing any\n",
+		"func MarshalBytes(v []byte) graphql.Marshaler {",
+		"func MarshalInt64(v int64) graphql.Marshaler {\n\treturn graphql.MarshalString(strconv.FormatInt(v, 10))\n}",
+		"func MarshalTimestamp(v *timestamppb.Timestamp) graphql.ContextMarshaler {\n\treturn marshalProtoJSON(v)\n}",
+		"func UnmarshalTimestamp(_ context.Context, v any) (*timestamppb.Timestamp, error) {",
+		"func unmarshalProtoJSON(v any, m proto.Message) error {",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("expected %s to contain %q\n%s", gqlgenSupportFile, want, src)
+		}
+	}
+
+	// Fields gqlgen wouldn't find by their GraphQL name are bound by Go name
+	out = runGenerator(t, Options{GqlgenModels: true, Naming: NamingJSONName}, map[string]string{"shop/v1/shop.proto": namingProto})
+	models = out[gqlgenModelsFile]
+	if want := "    fields:\n      title:\n        fieldName: DisplayName\n"; !strings.Contains(models, want) {
+		t.Errorf("expected %s to contain %q\n%s", gqlgenModelsFile, want, models)
+	}
+	if strings.Contains(models, "SKU:") {
+		t.Errorf("expected SKU to be bound by name\n%s", models)
+	}
+	if _, ok := out[gqlgenSupportFile]; ok {
+		t.Errorf("expected no %s without scalars or unions", gqlgenSupportFile)
+	}
+
+	plugin := newTestPlugin(t, sources)
+	g, err := NewGenerator(Options{GqlgenModels: true})
+	if err != nil {
+		t.Fatalf("failed to create generator: %v", err)
+	}
+	if err := g.Generate(plugin); err == nil || !strings.Contains(err.Error(), "gqlgen_package is required") {
+		t.Errorf("expected missing gqlgen_package error, got %v", err)
+	}
+}
+
+var outputModeProtos = map[string]string{
+	"common/v1/money.proto": `
+syntax = "proto3";
+package common.v1;
+
+message Money {
+  string currency_code = 1;
+  int64 units = 2;
+}
+`,
+	"shop/v1/catalog.proto": `
+syntax = "proto3";
+package shop.v1;
+
+import "common/v1/money.proto";
+
+service CatalogService {
+  rpc GetProduct(GetProductRequest) returns (Product) {}
+}
+
+message Product {
+  string sku = 1;
+  common.v1.Money price = 2;
+}
+
+message GetProductRequest { string sku = 1; }
+`,
+	"shop/v1/orders.proto": `
+syntax = "proto3";
+package shop.v1;
+
+import "shop/v1/catalog.proto";
+
+service OrderService {
+  rpc GetOrder(GetOrderRequest) returns (Order) {}
+}
+
+message Order {
+  string order_id = 1;
+  repeated Product products = 2;
+}
+
+message GetOrderRequest { string order_id = 1; }
+`,
+	"users/v1/users.proto": `
+syntax = "proto3";
+package users.v1;
+
+import "common/v1/money.proto";
+
+service UserService {
+  rpc GetUser(GetUserRequest) returns (User) {}
+  rpc CreateUser(CreateUserRequest) returns (User) {}
+}
+
+message User {
+  string user_id = 1;
+  common.v1.Money balance = 2;
+}
+
+message GetUserRequest { string user_id = 1; }
+message CreateUserRequest { string name = 1; }
+`,
+}
+
+func TestOutputModes(t *testing.T) {
+	tests := []struct {
+		mode string
+		// The schema files generated, with the operations in each
+		files map[string][]string
+	}{
+		{OutputModePerService, map[string][]string{
+			"shop.v1.CatalogService.graphql": {"GetProduct"},
+			"shop.v1.OrderService.graphql":   {"GetOrder"},
+			"users.v1.UserService.graphql":   {"GetUser", "CreateUser"},
+		}},
+		{OutputModePerFile, map[string][]string{
+			"shop.v1.catalog.graphql": {"GetProduct"},
+			"shop.v1.orders.graphql":  {"GetOrder"},
+			"users.v1.users.graphql":  {"GetUser", "CreateUser"},
+		}},
+		{OutputModePerPackage, map[string][]string{
+			"shop.v1.graphql":  {"GetProduct", "GetOrder"},
+			"users.v1.graphql": {"GetUser", "CreateUser"},
+		}},
+		{OutputModeMerged, map[string][]string{
+			"schema.graphql": {"GetProduct", "GetOrder", "GetUser", "CreateUser"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			out := runGenerator(t, Options{OutputMode: tt.mode}, outputModeProtos)
+			if len(out) != len(tt.files) {
+				t.Errorf("expected %d files, got %d", len(tt.files), len(out))
+			}
+			for name, operations := range tt.files {
+				schema, ok := out[name]
+				if !ok {
+					t.Errorf("expected %s to be generated", name)
+					continue
+				}
+				for _, op := range operations {
+					if !strings.Contains(schema, "  "+op+"(") {
+						t.Errorf("expected %s to contain operation %s\n%s", name, op, schema)
+					}
+				}
+				// The schema root, root types and shared types are declared once
+				for _, decl := range []string{"schema {", "extend type Query {", "type Money ", "type Product "} {
+					if n := strings.Count(schema, decl); n > 1 {
+						t.Errorf("expected %q to be declared at most once in %s, got %d\n%s", decl, name, n, schema)
+					}
+				}
+				if n := strings.Count(schema, "schema {"); n != 1 {
+					t.Errorf("expected one schema root in %s, got %d\n%s", name, n, schema)
+				}
+			}
+		})
+	}
+
+	schema := runGenerator(t, Options{OutputMode: OutputModeMerged}, outputModeProtos)["schema.graphql"]
+	for _, want := range []string{
+		"# Source: shop/v1/catalog.proto, shop/v1/orders.proto, users/v1/users.proto\n####",
+		"schema {\n  query: Query\n  mutation: Mutation\n}",
+		"extend type Query {\n  GetProduct(sku: String): Product\n  GetOrder(order_id: String): Order\n  GetUser(user_id: String): User\n}",
+		"type Product {\n  sku: String!\n  price: Money\n}",
+		"type Order {\n  order_id: String!\n  products: [Product!]!\n}",
+		"type User {\n  user_id: String!\n  balance: Money\n}",
+	} {
+		if !strings.Contains(schema, want) {
+			t.Errorf("expected merged schema to contain %q\n%s", want, schema)
+		}
+	}
+
+	// Services that aren't federated get a plain schema of their own
+	adminProtos := map[string]string{"shop/v1/shop.proto": `
+syntax = "proto3";
+package shop.v1;
+
+import "metadata/v1/metadata.proto";
+
+service CatalogService {
+  rpc GetProduct(GetProductRequest) returns (Product) {}
+}
+service AdminService {
+  option (metadata.v1.federated) = false;
+  rpc DeleteProduct(DeleteProductRequest) returns (Product) {}
+}
+
+message Product { string sku = 1; }
+message GetProductRequest { string sku = 1; }
+message DeleteProductRequest { string sku = 1; }
+`}
+	for mode, federatedName := range map[string]string{
+		OutputModePerFile:    "shop.v1.shop.graphql",
+		OutputModePerPackage: "shop.v1.graphql",
+		OutputModeMerged:     "schema.graphql",
+	} {
+		out := runGenerator(t, Options{OutputMode: mode}, adminProtos)
+		if len(out) != 2 {
+			t.Errorf("%s: expected 2 files, got %d", mode, len(out))
+		}
+		federated := out[federatedName]
+		if !strings.Contains(federated, "extend type Query {\n  GetProduct(sku: String): Product\n}") {
+			t.Errorf("%s: expected %s to extend Query with GetProduct\n%s", mode, federatedName, federated)
+		}
+		if strings.Contains(federated, "DeleteProduct") {
+			t.Errorf("%s: expected %s not to contain the AdminService\n%s", mode, federatedName, federated)
+		}
+		admin, ok := out["shop.v1.AdminService.graphql"]
+		if !ok {
+			t.Errorf("%s: expected shop.v1.AdminService.graphql to be generated", mode)
+			continue
+		}
+		if strings.Contains(admin, "@link") || strings.Contains(admin, "extend type") || !strings.Contains(admin, "type Mutation {\n  DeleteProduct(") {
+			t.Errorf("%s: expected a plain AdminService schema\n%s", mode, admin)
+		}
+	}
+
+	// Operations of different services can't share a root field
+	plugin := newTestPlugin(t, map[string]string{"shop/v1/shop.proto": `
+syntax = "proto3";
+package shop.v1;
+
+service CatalogService {
+  rpc GetProduct(GetProductRequest) returns (Product) {}
+}
+service InventoryService {
+  rpc GetProduct(GetProductRequest) returns (Product) {}
+}
+
+message Product { string sku = 1; }
+message GetProductRequest { string sku = 1; }
+`})
+	g, err := NewGenerator(Options{OutputMode: OutputModePerFile})
+	if err != nil {
+		t.Fatalf("failed to create generator: %v", err)
+	}
+	err = g.Generate(plugin)
+	if want := `shop.v1.CatalogService.GetProduct and shop.v1.InventoryService.GetProduct both generate Query field "GetProduct"`; err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("expected error containing %q, got %v", want, err)
+	}
+
+	if _, err := NewGenerator(Options{OutputMode: "per_method"}); err == nil {
+		t.Error("expected an error for an invalid output_mode option")
+	}
+}
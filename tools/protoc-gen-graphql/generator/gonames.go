@@ -0,0 +1,113 @@
+package generator
+
+import (
+	"go/token"
+	"strings"
+	"unicode"
+)
+
+// goInitialisms are the words gqlgen writes in upper case in Go names
+var goInitialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "AWS": true, "CPU": true,
+	"CSS": true, "CSV": true, "DNS": true, "EOF": true, "GCP": true,
+	"GUID": true, "HTML": true, "HTTP": true, "HTTPS": true, "ICMP": true,
+	"ID": true, "IP": true, "JSON": true, "KVK": true, "LHS": true,
+	"PDF": true, "PGP": true, "QPS": true, "QR": true, "RAM": true,
+	"RHS": true, "RPC": true, "SLA": true, "SMTP": true, "SQL": true,
+	"SSH": true, "SVG": true, "TCP": true, "TLS": true, "TTL": true,
+	"UDP": true, "UI": true, "UID": true, "URI": true, "URL": true,
+	"UTF8": true, "UUID": true, "VM": true, "XML": true, "XMPP": true,
+	"XSRF": true, "XSS": true,
+}
+
+// goName returns the exported Go name gqlgen gives a GraphQL name, for
+// types, fields and resolver methods (product_id becomes ProductID)
+func goName(name string) string {
+	var b strings.Builder
+	for _, w := range goWords(name) {
+		switch {
+		case w.initialism:
+			b.WriteString(strings.ToUpper(w.word))
+		case strings.ToUpper(w.word) == w.word || strings.ToLower(w.word) == w.word:
+			b.WriteString(upperFirst(strings.ToLower(w.word)))
+		default:
+			b.WriteString(w.word)
+		}
+	}
+	return b.String()
+}
+
+// goPrivateName returns the unexported Go name gqlgen gives a GraphQL name,
+// for resolver arguments (product_id becomes productID)
+func goPrivateName(name string) string {
+	var b strings.Builder
+	for i, w := range goWords(name) {
+		switch {
+		case i == 0 && (strings.ToUpper(w.word) == w.word || strings.ToLower(w.word) == w.word):
+			b.WriteString(strings.ToLower(w.word))
+		case i == 0:
+			b.WriteString(strings.ToLower(w.word[:1]) + w.word[1:])
+		case w.initialism:
+			b.WriteString(strings.ToUpper(w.word))
+		default:
+			b.WriteString(upperFirst(strings.ToLower(w.word)))
+		}
+	}
+	if token.IsKeyword(b.String()) {
+		return b.String() + "Arg"
+	}
+	return b.String()
+}
+
+// goWord is a word of a name split by goWords
+type goWord struct {
+	word       string
+	initialism bool
+}
+
+// goWords splits a name into words at delimiters and lower to upper case
+// transitions, keeping initialisms followed by another word apart (IDFoo is
+// ID and Foo)
+func goWords(name string) []goWord {
+	isDelimiter := func(r rune) bool {
+		return r == '_' || r == '-' || unicode.IsSpace(r)
+	}
+
+	var words []goWord
+	runes := []rune(strings.TrimFunc(name, isDelimiter))
+	start := 0
+	for i := 0; i < len(runes); i++ {
+		end := i+1 == len(runes)
+		if !end {
+			switch {
+			case isDelimiter(runes[i+1]):
+				end = true
+			case unicode.IsLower(runes[i]) && !unicode.IsLower(runes[i+1]):
+				end = true
+			case goInitialisms[string(runes[start:i+1])] && !unicode.IsLower(runes[i+1]):
+				end = true
+			}
+		}
+		if !end {
+			continue
+		}
+
+		word := string(runes[start : i+1])
+		words = append(words, goWord{word: word, initialism: goInitialisms[strings.ToUpper(word)]})
+		for i+1 < len(runes) && isDelimiter(runes[i+1]) {
+			i++
+		}
+		start = i + 1
+	}
+	return words
+}
+
+// upperFirst upper-cases the first letter of s
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
@@ -0,0 +1,105 @@
+package generator
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Input styles for the input_style plugin option
+const (
+	// Every field of the request message is a separate argument
+	InputStyleFlatten = "flatten"
+	// The request message is passed as a single input argument
+	InputStyleObject = "object"
+)
+
+// InputType is a GraphQL input object generated from a proto message
+type InputType struct {
+	Name    string
+	Fields  []*Field
+	Comment string
+
+	message *protogen.Message
+}
+
+// inputType returns the input object for msg, registering it and the input
+// objects for the messages it references the first time it is seen. Input
+// objects are named after the message with an Input suffix; if that name is
+// already taken a number is appended (ProductInput2).
+//
+// Update inputs leave out IMMUTABLE fields. Messages that have them, directly
+// or in the messages they refer to, get a separate update input object with
+// an UpdateInput suffix; other messages use the same input object.
+func (tm *typeMapper) inputType(msg *protogen.Message, update bool) *InputType {
+	update = update && hasImmutableFields(msg, make(map[protoreflect.FullName]bool))
+	seen, suffix := tm.seenInputs, "Input"
+	if update {
+		seen, suffix = tm.updateInputs, "UpdateInput"
+	}
+	if input, ok := seen[msg.Desc.FullName()]; ok {
+		return input
+	}
+
+	base := tm.typeName(msg.Desc)
+	name := base + suffix
+	for i := 2; tm.typeNames[name]; i++ {
+		name = fmt.Sprintf("%s%s%d", base, suffix, i)
+	}
+	tm.typeNames[name] = true
+
+	input := &InputType{
+		Name:    name,
+		Comment: description(msg.Comments),
+		message: msg,
+	}
+	// Register before extracting fields so recursive messages terminate
+	seen[msg.Desc.FullName()] = input
+	tm.inputs = append(tm.inputs, input)
+	input.Fields = extractInputFields(msg, update, tm)
+	return input
+}
+
+// Inputs returns the input objects used so far, in the order they were
+// first referenced
+func (tm *typeMapper) Inputs() []*InputType {
+	return tm.inputs
+}
+
+// extractInputFields converts the fields of msg to input object fields,
+// leaving out OUTPUT_ONLY fields, the page fields of paginated requests and,
+// for update inputs, IMMUTABLE fields
+func extractInputFields(msg *protogen.Message, update bool, tm *typeMapper) []*Field {
+	use := inputUse
+	if update {
+		use = updateInputUse
+	}
+
+	var fields []*Field
+	for _, f := range msg.Fields {
+		// A oneof becomes a single @oneOf input field
+		if isRealOneof(f) {
+			if f == f.Oneof.Fields[0] {
+				fields = append(fields, tm.oneofField(f.Oneof, true))
+			}
+			continue
+		}
+
+		if isOmitted(f, use) || tm.isPageField(f) {
+			continue
+		}
+
+		field := tm.newField(f, use)
+		if field.Constraints = fieldConstraints(f); field.Constraints != nil {
+			tm.useDirective("constraint")
+		}
+		// Required arguments and input fields can't be deprecated
+		if !field.NonNull {
+			field.Deprecated, field.DeprecationReason = fieldDeprecation(f)
+		}
+		field.Comment = deprecatedDescription(f.Comments, field.Deprecated)
+		fields = append(fields, field)
+	}
+	return fields
+}
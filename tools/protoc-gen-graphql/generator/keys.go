@@ -0,0 +1,148 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// Key is an @key directive of an entity
+type Key struct {
+	// The GraphQL selection set of the key (e.g. "tenantID sku")
+	Fields     string
+	Resolvable bool
+}
+
+// entityKeys returns the keys of the entity msg: one key made up of the
+// fields marked with (metadata.v1.key), followed by the keys declared with
+// the (metadata.v1.keys) option. Key selections use proto field names and
+// are rendered with the generator's naming option.
+func entityKeys(msg *protogen.Message, naming string) ([]*Key, error) {
+	var keys []*Key
+
+	var marked []string
+	for _, f := range msg.Fields {
+		if fieldAnnotations(f).Key {
+			marked = append(marked, fieldName(f, naming))
+		}
+	}
+	if len(marked) > 0 {
+		keys = append(keys, &Key{Fields: strings.Join(marked, " "), Resolvable: true})
+	}
+
+	for _, k := range messageAnnotations(msg).Keys {
+		fields, err := keySelection(msg, k.GetFields(), naming)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q of %s: %v", k.GetFields(), msg.Desc.FullName(), err)
+		}
+		keys = append(keys, &Key{
+			Fields:     fields,
+			Resolvable: k.Resolvable == nil || k.GetResolvable(),
+		})
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("entity %s has no key: mark a field with (metadata.v1.key) or declare (metadata.v1.keys)", msg.Desc.FullName())
+	}
+	return keys, nil
+}
+
+// hasKeys reports whether msg declares a key, either with the
+// (metadata.v1.keys) option or by marking a field with (metadata.v1.key)
+func hasKeys(msg *protogen.Message) bool {
+	if len(messageAnnotations(msg).Keys) > 0 {
+		return true
+	}
+	for _, f := range msg.Fields {
+		if fieldAnnotations(f).Key {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldSelection checks and renders the field set given by the field option
+// of f (e.g. requires), which selects fields of msg. It returns an empty
+// selection when the option is unset.
+func fieldSelection(f *protogen.Field, option string, msg *protogen.Message, fields string, naming string) (string, error) {
+	if fields == "" {
+		return "", nil
+	}
+	if msg == nil || isWellKnownType(msg) || f.Desc.IsMap() {
+		return "", fmt.Errorf("invalid %s %q of %s: %s has no fields to select", option, fields, f.Desc.FullName(), f.Desc.Name())
+	}
+	selection, err := keySelection(msg, fields, naming)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s %q of %s: %v", option, fields, f.Desc.FullName(), err)
+	}
+	return selection, nil
+}
+
+// keySelection checks that the selection set of proto field names in
+// fields exists on msg and returns it with GraphQL field names
+func keySelection(msg *protogen.Message, fields string, naming string) (string, error) {
+	tokens := strings.Fields(strings.NewReplacer("{", " { ", "}", " } ").Replace(fields))
+	if len(tokens) == 0 {
+		return "", fmt.Errorf("no fields")
+	}
+
+	selection, rest, err := parseSelection(msg, tokens, naming)
+	if err != nil {
+		return "", err
+	}
+	if len(rest) > 0 {
+		return "", fmt.Errorf("unexpected %q", rest[0])
+	}
+	return strings.Join(selection, " "), nil
+}
+
+// parseSelection parses the fields of msg from tokens up to the end of the
+// current selection set, returning the rendered tokens and the tokens left
+func parseSelection(msg *protogen.Message, tokens []string, naming string) ([]string, []string, error) {
+	var out []string
+	for len(tokens) > 0 && tokens[0] != "}" {
+		name := tokens[0]
+		if name == "{" {
+			return nil, nil, fmt.Errorf("unexpected %q", name)
+		}
+		var field *protogen.Field
+		for _, f := range msg.Fields {
+			if string(f.Desc.Name()) == name {
+				field = f
+			}
+		}
+		if field == nil {
+			return nil, nil, fmt.Errorf("%s has no field %q", msg.Desc.FullName(), name)
+		}
+		out = append(out, fieldName(field, naming))
+		tokens = tokens[1:]
+
+		isObject := field.Message != nil && !isWellKnownType(field.Message) && !field.Desc.IsMap()
+		if len(tokens) == 0 || tokens[0] != "{" {
+			if isObject {
+				return nil, nil, fmt.Errorf("field %q of %s needs a selection of its fields", name, msg.Desc.FullName())
+			}
+			continue
+		}
+		if !isObject {
+			return nil, nil, fmt.Errorf("field %q of %s has no fields to select", name, msg.Desc.FullName())
+		}
+
+		nested, rest, err := parseSelection(field.Message, tokens[1:], naming)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(nested) == 0 {
+			return nil, nil, fmt.Errorf("empty selection for field %q of %s", name, msg.Desc.FullName())
+		}
+		if len(rest) == 0 {
+			return nil, nil, fmt.Errorf("missing closing brace")
+		}
+		out = append(out, "{")
+		out = append(out, nested...)
+		out = append(out, "}")
+		tokens = rest[1:]
+	}
+	return out, tokens, nil
+}
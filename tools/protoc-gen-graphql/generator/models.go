@@ -0,0 +1,467 @@
+package generator
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// Files generated with the gqlgen_models option: the gqlgen.yml models
+// section, and the Go types it binds to that have no protobuf counterpart
+const (
+	gqlgenModelsFile  = "gqlgen.models.yml"
+	gqlgenSupportFile = "gqlgen.models.go"
+)
+
+const (
+	gqlgenPackage  = protogen.GoImportPath("github.com/99designs/gqlgen/graphql")
+	base64Package  = protogen.GoImportPath("encoding/base64")
+	strconvPackage = protogen.GoImportPath("strconv")
+	ioPackage      = protogen.GoImportPath("io")
+	mathPackage    = protogen.GoImportPath("math")
+)
+
+// scalarModel is a gqlgen model of a scalar, binding it to a Go type: the
+// Go type of a protobuf scalar kind, or the full name of a well-known type
+type scalarModel struct {
+	GoType string
+	Model  string
+}
+
+// gqlgenBuiltinModels are the graphql package models gqlgen binds the
+// builtin scalars to by default
+var gqlgenBuiltinModels = map[string][]scalarModel{
+	"Int":     {{"int", "Int"}, {"int32", "Int32"}, {"int64", "Int64"}},
+	"Float":   {{"float64", "FloatContext"}},
+	"String":  {{"string", "String"}},
+	"Boolean": {{"bool", "Boolean"}},
+	"ID":      {{"string", "ID"}, {"int", "IntID"}},
+}
+
+// scalarMarshalers are the models generated for the Go types of the custom
+// scalars' protobuf fields. They are generated as MarshalX and UnmarshalX
+// functions, and serialize the values as the scalars' descriptions say; the
+// well-known types use their JSON encoding. The first model of a scalar is
+// the one gqlgen generates its own models with. The builtin scalars are
+// bound in gqlgen.yml, so they have none.
+var scalarMarshalers = map[string][]scalarModel{
+	"Int64": {
+		{"int64", "Int64"},
+		{"uint32", "Uint32"},
+		{"google.protobuf.Int64Value", "Int64Value"},
+		{"google.protobuf.UInt32Value", "UInt32Value"},
+	},
+	"UInt64": {
+		{"uint64", "Uint64"},
+		{"google.protobuf.UInt64Value", "UInt64Value"},
+	},
+	"Base64": {
+		{"[]byte", "Bytes"},
+		{"google.protobuf.BytesValue", "BytesValue"},
+	},
+	"DateTime":  {{"google.protobuf.Timestamp", "Timestamp"}},
+	"Duration":  {{"google.protobuf.Duration", "Duration"}},
+	"FieldMask": {{"google.protobuf.FieldMask", "FieldMask"}},
+	"JSON": {
+		{"google.protobuf.Value", "Value"},
+		{"google.protobuf.Struct", "Struct"},
+		{"google.protobuf.ListValue", "ListValue"},
+		{"google.protobuf.Any", "Any"},
+	},
+}
+
+// defaultScalarModel returns the model of the GraphQL scalar name gqlgen
+// generates its own models with: its default model for the builtin scalars,
+// and the first generated marshaler for the custom scalars
+func defaultScalarModel(name string) (scalarModel, bool) {
+	if models, ok := gqlgenBuiltinModels[name]; ok {
+		return models[0], true
+	}
+	if models, ok := scalarMarshalers[name]; ok {
+		return models[0], true
+	}
+	return scalarModel{}, false
+}
+
+// parseScalarModel parses a "Scalar:model" scalar binding, as passed with
+// the scalar_model plugin option (e.g.
+// scalar_model=Int:github.com/99designs/gqlgen/graphql.Int32). The model is
+// one of gqlgen's own or a generated marshaler, and gives the Go type the
+// scalar is converted to.
+func parseScalarModel(models map[string]scalarModel, value string) error {
+	scalar, model, ok := strings.Cut(value, ":")
+	dot := strings.LastIndex(model, ".")
+	if !ok || scalar == "" || dot <= 0 {
+		return fmt.Errorf("invalid scalar model %q, expected Scalar:model", value)
+	}
+	pkg, name := model[:dot], model[dot+1:]
+
+	known := scalarMarshalers
+	if protogen.GoImportPath(pkg) == gqlgenPackage {
+		known = gqlgenBuiltinModels
+	}
+	for _, candidates := range known {
+		for _, m := range candidates {
+			if m.Model == name {
+				models[scalar] = m
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("invalid scalar model %q, unknown model %q", value, model)
+}
+
+// protoMessageMethods are the methods of generated protobuf messages gqlgen
+// could bind a field to instead of the struct field of the same name
+var protoMessageMethods = []string{"Descriptor", "ProtoMessage", "ProtoReflect", "Reset", "String"}
+
+// modelsGenerator generates the gqlgen models binding the GraphQL types of
+// the schemas to the protobuf Go types, so gqlgen resolves them from the
+// protobuf messages instead of generating its own models
+type modelsGenerator struct {
+	// The plugin generating the models, and the package of their Go types
+	plugin string
+	pkg    protogen.GoImportPath
+	// The GraphQL types bound, by name
+	types   map[string]bool
+	objects []*Message
+	inputs  []*InputType
+	enums   []*Enum
+	unions  []*Oneof
+	// The scalars bound to generated marshalers, in the order they are
+	// first used, and their models
+	scalars      []string
+	scalarModels map[string][]string
+	// The generated marshalers, in the order they are first used
+	marshalers []scalarModel
+}
+
+func newModelsGenerator(plugin, pkg string) *modelsGenerator {
+	return &modelsGenerator{
+		plugin:       plugin,
+		pkg:          protogen.GoImportPath(pkg),
+		types:        make(map[string]bool),
+		scalarModels: make(map[string][]string),
+	}
+}
+
+// registerTypes records the types of a schema bound to protobuf Go types:
+// objects, inputs, enums and oneof unions. Types generated by several
+// schemas are bound once.
+func (mg *modelsGenerator) registerTypes(data *TemplateData) {
+	for _, msg := range data.Messages {
+		if !mg.types[msg.Name] {
+			mg.types[msg.Name] = true
+			mg.objects = append(mg.objects, msg)
+		}
+	}
+	for _, input := range data.Inputs {
+		if !mg.types[input.Name] {
+			mg.types[input.Name] = true
+			mg.inputs = append(mg.inputs, input)
+		}
+	}
+	for _, enum := range data.Enums {
+		if !mg.types[enum.Name] {
+			mg.types[enum.Name] = true
+			mg.enums = append(mg.enums, enum)
+		}
+	}
+	for _, oneof := range data.Oneofs {
+		if oneof.Output && !mg.types[oneof.Name] {
+			mg.types[oneof.Name] = true
+			mg.unions = append(mg.unions, oneof)
+		}
+	}
+}
+
+// goTypePath returns the path gqlgen refers to a Go type by
+func goTypePath(ident protogen.GoIdent) string {
+	return string(ident.GoImportPath) + "." + ident.GoName
+}
+
+// generate generates the models section, and the Go types it binds to if
+// any are needed
+func (mg *modelsGenerator) generate(gen *protogen.Plugin) error {
+	gf := gen.NewGeneratedFile(gqlgenModelsFile, "")
+	gf.P("# Code generated by ", mg.plugin, ". DO NOT EDIT.")
+	gf.P("#")
+	gf.P("# Binds the GraphQL types to the protobuf Go types. Merge into gqlgen.yml.")
+	gf.P("models:")
+
+	for _, msg := range mg.objects {
+		mg.generateModel(gf, msg.Name, goTypePath(msg.message.GoIdent), msg.Fields)
+	}
+	for _, input := range mg.inputs {
+		mg.generateModel(gf, input.Name, goTypePath(input.message.GoIdent), input.Fields)
+	}
+	for _, enum := range mg.enums {
+		gf.P("  ", enum.Name, ":")
+		gf.P("    model:")
+		gf.P("      - ", goTypePath(enum.enum.GoIdent))
+		gf.P("    enum_values:")
+		for _, v := range enum.Values {
+			gf.P("      ", v.Name, ":")
+			gf.P("        value: ", goTypePath(v.value.GoIdent))
+		}
+	}
+	for _, union := range mg.unions {
+		gf.P("  ", union.Name, ":")
+		gf.P("    model:")
+		gf.P("      - ", string(mg.pkg), ".", union.Name)
+	}
+	for _, scalar := range mg.scalars {
+		gf.P("  ", scalar, ":")
+		gf.P("    model:")
+		// In the order of scalarMarshalers, so gqlgen generates its own
+		// models with the first
+		for _, m := range scalarMarshalers[scalar] {
+			if model := string(mg.pkg) + "." + m.Model; slices.Contains(mg.scalarModels[scalar], model) {
+				gf.P("      - ", model)
+			}
+		}
+	}
+
+	if len(mg.unions) == 0 && len(mg.marshalers) == 0 {
+		return nil
+	}
+	if mg.pkg == "" {
+		return fmt.Errorf("gqlgen_package is required to bind the scalars and unions of the gqlgen models")
+	}
+	mg.generateSupport(gen)
+	return nil
+}
+
+// generateModel generates the model of an object or input bound to the
+// protobuf Go type goType
+func (mg *modelsGenerator) generateModel(gf *protogen.GeneratedFile, name, goType string, fields []*Field) {
+	gf.P("  ", name, ":")
+	gf.P("    model:")
+	gf.P("      - ", goType)
+
+	first := true
+	for _, f := range fields {
+		resolver, fieldName := mg.fieldBinding(f)
+		if !resolver && fieldName == "" {
+			continue
+		}
+		if first {
+			gf.P("    fields:")
+			first = false
+		}
+		gf.P("      ", f.Name, ":")
+		if resolver {
+			gf.P("        resolver: true")
+		} else {
+			gf.P("        fieldName: ", fieldName)
+		}
+	}
+}
+
+// fieldBinding returns how gqlgen binds a field to its protobuf Go struct
+// field: with a resolver when the field's Go type can't be bound to its
+// GraphQL type, as for maps, oneofs, google.protobuf.Empty, enums whose
+// zero value is null and builtin scalars gqlgen has no model for (float and
+// the wrappers), or by Go field name when gqlgen wouldn't find it from
+// the GraphQL name. Both are
+// unset for fields bound by name.
+func (mg *modelsGenerator) fieldBinding(f *Field) (resolver bool, fieldName string) {
+	pf := f.field
+	if pf == nil || f.Map != nil {
+		return true, ""
+	}
+	switch {
+	case pf.Enum != nil:
+		if !f.NonNull && !f.List && zeroIsNull(mg.enums, f.GraphQLType) {
+			return true, ""
+		}
+	case pf.Message != nil && !isWellKnownType(pf.Message):
+	case pf.Message != nil:
+		if !mg.bindScalar(f.GraphQLType, string(pf.Message.Desc.FullName())) {
+			return true, ""
+		}
+	default:
+		if !mg.bindScalar(f.GraphQLType, protoGoTypes[pf.Desc.Kind()]) {
+			return true, ""
+		}
+	}
+
+	// gqlgen looks the GraphQL name up among the methods first, then the
+	// fields, ignoring case and underscores
+	name := goName(f.Name)
+	for _, method := range protoMessageMethods {
+		if equalFieldName(name, method) {
+			return false, pf.GoName
+		}
+	}
+	if !equalFieldName(name, pf.GoName) {
+		return false, pf.GoName
+	}
+	return false, ""
+}
+
+// equalFieldName reports whether gqlgen considers a GraphQL field's Go name
+// and a Go field name the same
+func equalFieldName(a, b string) bool {
+	return strings.EqualFold(strings.ReplaceAll(a, "_", ""), strings.ReplaceAll(b, "_", ""))
+}
+
+// bindScalar reports whether the scalar has a model for goType, the Go type
+// of a protobuf scalar kind or the full name of a well-known type,
+// registering the generated marshaler of a custom scalar. The builtin
+// scalars keep the models gqlgen.yml binds them to, which are assumed to
+// be gqlgen's defaults.
+func (mg *modelsGenerator) bindScalar(scalar, goType string) bool {
+	if models, ok := gqlgenBuiltinModels[scalar]; ok {
+		for _, m := range models {
+			if m.GoType == goType {
+				return true
+			}
+		}
+		return false
+	}
+	for _, m := range scalarMarshalers[scalar] {
+		if m.GoType != goType {
+			continue
+		}
+		model := string(mg.pkg) + "." + m.Model
+		for _, seen := range mg.scalarModels[scalar] {
+			if seen == model {
+				return true
+			}
+		}
+		if len(mg.scalarModels[scalar]) == 0 {
+			mg.scalars = append(mg.scalars, scalar)
+		}
+		mg.scalarModels[scalar] = append(mg.scalarModels[scalar], model)
+		mg.marshalers = append(mg.marshalers, m)
+		return true
+	}
+	return false
+}
+
+// generateSupport generates the Go types the models bind to: a type for
+// each union, which the protobuf messages and gqlgen's models of wrapped
+// members both satisfy, and the scalar marshalers
+func (mg *modelsGenerator) generateSupport(gen *protogen.Plugin) {
+	gf := gen.NewGeneratedFile(gqlgenSupportFile, mg.pkg)
+	gf.P("// Code generated by ", mg.plugin, ". DO NOT EDIT.")
+	gf.P()
+	gf.P("package ", path.Base(string(mg.pkg)))
+	gf.P()
+
+	for _, union := range mg.unions {
+		gf.P("// ", union.Name, " is a member of the ", union.Name, " union: the protobuf message of")
+		gf.P("// a member type, or the gqlgen model of a wrapped member")
+		gf.P("type ", union.Name, " any")
+		gf.P()
+	}
+
+	marshaler := gf.QualifiedGoIdent(gqlgenPackage.Ident("Marshaler"))
+	protoJSON := false
+	for _, m := range mg.marshalers {
+		name := m.Model
+		if msg, ok := wellKnownGoTypes[m.GoType]; ok {
+			protoJSON = true
+			goType := "*" + gf.QualifiedGoIdent(msg)
+			gf.P("// Marshal", name, " marshals a ", m.GoType, " in its JSON encoding")
+			gf.P("func Marshal", name, "(v ", goType, ") ", gqlgenPackage.Ident("ContextMarshaler"), " {")
+			gf.P("return marshalProtoJSON(v)")
+			gf.P("}")
+			gf.P()
+			gf.P("// Unmarshal", name, " unmarshals a ", m.GoType, " from its JSON encoding")
+			gf.P("func Unmarshal", name, "(_ ", contextPackage.Ident("Context"), ", v any) (", goType, ", error) {")
+			gf.P("m := &", msg, "{}")
+			gf.P("if err := unmarshalProtoJSON(v, m); err != nil {")
+			gf.P("return nil, err")
+			gf.P("}")
+			gf.P("return m, nil")
+			gf.P("}")
+			gf.P()
+			continue
+		}
+
+		switch name {
+		case "Int64", "Uint64":
+			goType, format, parse := "int64", "FormatInt", "ParseInt"
+			if name == "Uint64" {
+				goType, format, parse = "uint64", "FormatUint", "ParseUint"
+			}
+			gf.P("// Marshal", name, " marshals an ", goType, " as a string, which doesn't lose precision")
+			gf.P("func Marshal", name, "(v ", goType, ") ", marshaler, " {")
+			gf.P("return ", gqlgenPackage.Ident("MarshalString"), "(", strconvPackage.Ident(format), "(v, 10))")
+			gf.P("}")
+			gf.P()
+			gf.P("// Unmarshal", name, " unmarshals an ", goType, " from a string or a number")
+			gf.P("func Unmarshal", name, "(v any) (", goType, ", error) {")
+			gf.P("if s, ok := v.(string); ok {")
+			gf.P("return ", strconvPackage.Ident(parse), "(s, 10, 64)")
+			gf.P("}")
+			gf.P("return ", gqlgenPackage.Ident("Unmarshal"+name), "(v)")
+			gf.P("}")
+		case "Uint32":
+			gf.P("// MarshalUint32 marshals a uint32 as a string, like the other Int64 values")
+			gf.P("func MarshalUint32(v uint32) ", marshaler, " {")
+			gf.P("return ", gqlgenPackage.Ident("MarshalString"), "(", strconvPackage.Ident("FormatUint"), "(uint64(v), 10))")
+			gf.P("}")
+			gf.P()
+			gf.P("// UnmarshalUint32 unmarshals a uint32 from a string or a number")
+			gf.P("func UnmarshalUint32(v any) (uint32, error) {")
+			gf.P("if s, ok := v.(string); ok {")
+			gf.P("i, err := ", strconvPackage.Ident("ParseUint"), "(s, 10, 32)")
+			gf.P("return uint32(i), err")
+			gf.P("}")
+			gf.P("i, err := ", gqlgenPackage.Ident("UnmarshalInt64"), "(v)")
+			gf.P("if err != nil {")
+			gf.P("return 0, err")
+			gf.P("}")
+			gf.P("if i < 0 || i > ", mathPackage.Ident("MaxUint32"), " {")
+			gf.P("return 0, ", fmtPackage.Ident("Errorf"), "(\"%d overflows uint32\", i)")
+			gf.P("}")
+			gf.P("return uint32(i), nil")
+			gf.P("}")
+		case "Bytes":
+			gf.P("// MarshalBytes marshals bytes as a standard base64 encoded string")
+			gf.P("func MarshalBytes(v []byte) ", marshaler, " {")
+			gf.P("return ", gqlgenPackage.Ident("MarshalString"), "(", base64Package.Ident("StdEncoding"), ".EncodeToString(v))")
+			gf.P("}")
+			gf.P()
+			gf.P("// UnmarshalBytes unmarshals bytes from a standard base64 encoded string")
+			gf.P("func UnmarshalBytes(v any) ([]byte, error) {")
+			gf.P("s, err := ", gqlgenPackage.Ident("UnmarshalString"), "(v)")
+			gf.P("if err != nil {")
+			gf.P("return nil, err")
+			gf.P("}")
+			gf.P("return ", base64Package.Ident("StdEncoding"), ".DecodeString(s)")
+			gf.P("}")
+		}
+		gf.P()
+	}
+
+	if !protoJSON {
+		return
+	}
+	gf.P("// marshalProtoJSON marshals m in its JSON encoding")
+	gf.P("func marshalProtoJSON(m ", protoPackage.Ident("Message"), ") ", gqlgenPackage.Ident("ContextMarshaler"), " {")
+	gf.P("return ", gqlgenPackage.Ident("ContextWriterFunc"), "(func(_ ", contextPackage.Ident("Context"), ", w ", ioPackage.Ident("Writer"), ") error {")
+	gf.P("b, err := ", protojsonPackage.Ident("Marshal"), "(m)")
+	gf.P("if err != nil {")
+	gf.P("return err")
+	gf.P("}")
+	gf.P("_, err = w.Write(b)")
+	gf.P("return err")
+	gf.P("})")
+	gf.P("}")
+	gf.P()
+	gf.P("// unmarshalProtoJSON sets m from the JSON encoding of v")
+	gf.P("func unmarshalProtoJSON(v any, m ", protoPackage.Ident("Message"), ") error {")
+	gf.P("b, err := ", jsonPackage.Ident("Marshal"), "(v)")
+	gf.P("if err != nil {")
+	gf.P("return err")
+	gf.P("}")
+	gf.P("return ", protojsonPackage.Ident("Unmarshal"), "(b, m)")
+	gf.P("}")
+}
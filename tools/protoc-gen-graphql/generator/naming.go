@@ -0,0 +1,183 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// Naming conventions for the naming plugin option
+const (
+	// Field names are used as written in the proto (product_id)
+	NamingProto = "proto"
+	// Field names use their proto JSON name (productId)
+	NamingJSONName = "json_name"
+	// Field names are lowerCamelCase with common initialisms (productID)
+	NamingLowerCamel = "lowerCamel"
+)
+
+// Naming conventions for the operation_naming plugin option
+const (
+	// Operations use the method name (GetProduct)
+	OperationNamingProto = "proto"
+	// Operations use the lowerCamelCase method name (getProduct)
+	OperationNamingLowerCamel = "lowerCamel"
+	// Get and List verbs are stripped and the rest is lowerCamelCase
+	// (GetProduct becomes product, ListProducts becomes products)
+	OperationNamingStripVerbs = "strip_verbs"
+)
+
+// initialisms are the words lowerCamel naming writes in upper case
+var initialisms = map[string]bool{
+	"ID":   true,
+	"URL":  true,
+	"URI":  true,
+	"API":  true,
+	"HTTP": true,
+	"JSON": true,
+	"UUID": true,
+	"IP":   true,
+}
+
+// validateNaming checks the naming plugin options
+func validateNaming(opts Options) error {
+	switch opts.Naming {
+	case "", NamingProto, NamingJSONName, NamingLowerCamel:
+	default:
+		return fmt.Errorf("invalid naming %q, expected %s, %s or %s", opts.Naming, NamingProto, NamingJSONName, NamingLowerCamel)
+	}
+	switch opts.OperationNaming {
+	case "", OperationNamingProto, OperationNamingLowerCamel, OperationNamingStripVerbs:
+	default:
+		return fmt.Errorf("invalid operation_naming %q, expected %s, %s or %s", opts.OperationNaming, OperationNamingProto, OperationNamingLowerCamel, OperationNamingStripVerbs)
+	}
+	return nil
+}
+
+// fieldName returns the GraphQL name of a field, honoring the
+// (metadata.v1.field_name) override
+func fieldName(f *protogen.Field, naming string) string {
+	if name := fieldAnnotations(f).FieldName; name != "" {
+		return name
+	}
+	if naming == NamingJSONName {
+		return f.Desc.JSONName()
+	}
+	return applyNaming(string(f.Desc.Name()), naming)
+}
+
+// applyNaming converts a snake_case proto name using the naming convention.
+// Names without a JSON name of their own (e.g. oneofs) use lowerCamelCase
+// for json_name.
+func applyNaming(name, naming string) string {
+	switch naming {
+	case NamingJSONName:
+		return lowerCamelCase(name, false)
+	case NamingLowerCamel:
+		return lowerCamelCase(name, true)
+	}
+	return name
+}
+
+// operationName returns the GraphQL name of a method, honoring the
+// (metadata.v1.operation_name) override
+func operationName(method *protogen.Method, naming string) string {
+	if name := methodAnnotations(method).OperationName; name != "" {
+		return name
+	}
+
+	name := string(method.Desc.Name())
+	switch naming {
+	case OperationNamingLowerCamel:
+		return lowerFirst(name)
+	case OperationNamingStripVerbs:
+		for _, verb := range []string{"Get", "List"} {
+			rest := strings.TrimPrefix(name, verb)
+			if rest != name && rest != "" && unicode.IsUpper(rune(rest[0])) {
+				return lowerFirst(rest)
+			}
+		}
+		return lowerFirst(name)
+	}
+	return name
+}
+
+// lowerCamelCase converts a snake_case name to lowerCamelCase, optionally
+// writing common initialisms in upper case (product_id becomes productID)
+func lowerCamelCase(s string, withInitialisms bool) string {
+	var b strings.Builder
+	for i, word := range strings.Split(s, "_") {
+		if word == "" {
+			continue
+		}
+		if i == 0 || b.Len() == 0 {
+			b.WriteString(strings.ToLower(word[:1]) + word[1:])
+			continue
+		}
+		if withInitialisms {
+			upper := strings.ToUpper(word)
+			if initialisms[upper] {
+				b.WriteString(upper)
+				continue
+			}
+			// Plural initialisms keep a lower case s (IDs)
+			if singular := strings.TrimSuffix(upper, "S"); singular != upper && initialisms[singular] {
+				b.WriteString(singular + "s")
+				continue
+			}
+		}
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return b.String()
+}
+
+// lowerFirst lower-cases the leading capitals of a CamelCase name, keeping
+// the last one of an initialism (HTTPServer becomes httpServer)
+func lowerFirst(s string) string {
+	runes := []rune(s)
+	for i := 0; i < len(runes) && unicode.IsUpper(runes[i]); i++ {
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
+
+// camelCase converts a snake_case name to CamelCase
+func camelCase(s string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range s {
+		if r == '_' {
+			upper = true
+			continue
+		}
+		if upper {
+			b.WriteString(strings.ToUpper(string(r)))
+			upper = false
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// screamingSnakeCase converts a CamelCase name to SCREAMING_SNAKE_CASE
+func screamingSnakeCase(s string) string {
+	var b strings.Builder
+	runes := []rune(s)
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,105 @@
+package generator
+
+import "google.golang.org/protobuf/compiler/protogen"
+
+// Oneof is a proto oneof. Output types render it as a union of its members
+// and input types as a @oneOf input object named Name + "Input".
+type Oneof struct {
+	Name    string
+	Members []*OneofMember
+	Comment string
+	// Whether the oneof is used by output types and input arguments
+	Output bool
+	Input  bool
+}
+
+// OneofMember is a single field of a oneof
+type OneofMember struct {
+	// The union member type: the field's message type, or a generated
+	// wrapper object for scalars, enums and duplicated message types
+	TypeName string
+	Wrapped  bool
+	// The member as a field of its wrapper object and, once the oneof is
+	// used as an input, of the input object
+	Field      *Field
+	InputField *Field
+}
+
+// isRealOneof reports whether f belongs to a oneof declared in the proto
+// source, as opposed to the synthetic oneof of a proto3 optional field
+func isRealOneof(f *protogen.Field) bool {
+	return f.Oneof != nil && !f.Oneof.Desc.IsSynthetic()
+}
+
+// oneofField returns the field standing in for the oneof o on its message:
+// the union for output types, or the @oneOf input object for inputs
+func (tm *typeMapper) oneofField(o *protogen.Oneof, input bool) *Field {
+	oneof := tm.oneof(o)
+	field := &Field{
+		Name:        applyNaming(string(o.Desc.Name()), tm.opts.Naming),
+		ProtoName:   string(o.Desc.Name()),
+		GraphQLType: oneof.Name,
+		Oneof:       oneof,
+		Comment:     oneof.Comment,
+	}
+	if input {
+		if !oneof.Input {
+			oneof.Input = true
+			for i, f := range o.Fields {
+				member := oneof.Members[i]
+				member.InputField = tm.newField(f, inputUse)
+				member.InputField.NonNull = false
+				member.InputField.Comment = member.Field.Comment
+			}
+		}
+		field.GraphQLType += "Input"
+	} else {
+		oneof.Output = true
+	}
+	return field
+}
+
+// oneof returns the generated types for o, registering them the first time
+// the oneof is seen. The union is named after the message and the oneof
+// (Payment.method becomes PaymentMethod) and wrapper objects after the
+// message and the member field (PaymentVoucherCode).
+func (tm *typeMapper) oneof(o *protogen.Oneof) *Oneof {
+	if oneof, ok := tm.seenOneofs[o.Desc.FullName()]; ok {
+		return oneof
+	}
+
+	parent := tm.typeName(o.Parent.Desc)
+	oneof := &Oneof{
+		Name:    tm.generatedTypeName(o.Desc, parent+camelCase(string(o.Desc.Name()))),
+		Comment: description(o.Comments),
+	}
+	tm.seenOneofs[o.Desc.FullName()] = oneof
+	tm.oneofs = append(tm.oneofs, oneof)
+
+	seenTypes := make(map[string]bool)
+	for _, f := range o.Fields {
+		member := &OneofMember{
+			Field: tm.newField(f, outputUse),
+		}
+		member.Field.NonNull = true
+		member.Field.Comment = description(f.Comments)
+
+		// Union members must be distinct object types
+		if f.Message != nil && !isWellKnownType(f.Message) && !seenTypes[member.Field.GraphQLType] {
+			member.TypeName = member.Field.GraphQLType
+		} else {
+			member.TypeName = tm.generatedTypeName(f.Desc, parent+camelCase(string(f.Desc.Name())))
+			member.Wrapped = true
+		}
+		seenTypes[member.TypeName] = true
+
+		oneof.Members = append(oneof.Members, member)
+	}
+	return oneof
+}
+
+// Oneofs returns the generated types for the oneofs used so far, in the
+// order they were first referenced
+func (tm *typeMapper) Oneofs() []*Oneof {
+	return tm.oneofs
+}
@@ -0,0 +1,63 @@
+package generator
+
+import "flag"
+
+// Options for the generator
+type Options struct {
+	TemplatePath string   // Path to custom template file (falls back to embedded template if not provided)
+	Scalars      []string // Overrides for the protobuf to GraphQL scalar mapping, as kind:Type
+
+	EnumDropUnspecified bool   // Leave the FOO_UNSPECIFIED zero value out of generated enums
+	InputStyle          string // How request messages become arguments: flatten or object
+
+	MutationPrefixes []string // Extra method name prefixes classified as mutations
+
+	Naming          string // Field and argument naming: proto, json_name or lowerCamel
+	OperationNaming string // Operation naming: proto, lowerCamel or strip_verbs
+
+	FederatedOnly bool // Only generate services that set (metadata.v1.federated) = true
+
+	TypeCollisions string // What to do when types from different packages share a name: prefix or error
+
+	Strict bool // Fail on methods that can't be exposed, such as client-streaming RPCs, instead of skipping them
+
+	OutputMode string // How services are split into schema files: per_service, per_file, per_package or merged
+
+	ResolverPackage string   // Go import path of the package gqlgen resolvers are generated in
+	ModelPackage    string   // Go import path of the gqlgen models, defaults to the model package under ResolverPackage
+	ScalarModels    []string // The gqlgen models scalars are bound to in gqlgen.yml, as Scalar:model
+
+	GqlgenModels  bool   // Also generate a gqlgen.yml models section binding the schema types to the protobuf Go types
+	GqlgenPackage string // Go import path of the package the Go types the gqlgen models bind to are generated in
+}
+
+// RegisterFlags registers the plugin parameters setting opts on flags. Both
+// plugins accept the same parameters, so the resolvers are generated for the
+// same schemas.
+func (opts *Options) RegisterFlags(flags *flag.FlagSet) {
+	flags.StringVar(&opts.TemplatePath, "template_path", "", "Path to custom template file (falls back to embedded template if not provided)")
+	flags.Func("scalar", "Override the GraphQL type for a protobuf scalar type, as kind:Type (e.g. int64:String); may be repeated", func(s string) error {
+		opts.Scalars = append(opts.Scalars, s)
+		return nil
+	})
+	flags.StringVar(&opts.InputStyle, "input_style", InputStyleFlatten, "How request messages become arguments: flatten (one argument per field) or object (a single input argument)")
+	flags.Func("mutation_prefix", "Extra method name prefix classified as a mutation (e.g. Archive); may be repeated", func(s string) error {
+		opts.MutationPrefixes = append(opts.MutationPrefixes, s)
+		return nil
+	})
+	flags.StringVar(&opts.Naming, "naming", NamingProto, "Field and argument naming: proto (product_id), json_name (productId) or lowerCamel (productID)")
+	flags.StringVar(&opts.OperationNaming, "operation_naming", OperationNamingProto, "Operation naming: proto (GetProduct), lowerCamel (getProduct) or strip_verbs (product)")
+	flags.BoolVar(&opts.FederatedOnly, "federated_only", false, "Only generate services that set (metadata.v1.federated) = true, skipping all others")
+	flags.StringVar(&opts.TypeCollisions, "type_collisions", TypeCollisionsPrefix, "What to do when types from different packages share a name: prefix (qualify them with their package) or error")
+	flags.BoolVar(&opts.Strict, "strict", false, "Fail on methods that can't be exposed in GraphQL, such as client-streaming and bidirectional streaming RPCs, instead of skipping them with a warning")
+	flags.StringVar(&opts.OutputMode, "output_mode", OutputModePerService, "How services are split into schema files: per_service (one per service), per_file (one per proto file), per_package (one per proto package) or merged (a single schema.graphql)")
+	flags.StringVar(&opts.ResolverPackage, "resolver_package", "", "Go import path of the package the gqlgen resolvers are generated in")
+	flags.StringVar(&opts.ModelPackage, "model_package", "", "Go import path of the gqlgen models (defaults to resolver_package/model)")
+	flags.Func("scalar_model", "The first gqlgen model a scalar is bound to in gqlgen.yml, as Scalar:model (e.g. Int:github.com/99designs/gqlgen/graphql.Int32); may be repeated", func(s string) error {
+		opts.ScalarModels = append(opts.ScalarModels, s)
+		return nil
+	})
+	flags.BoolVar(&opts.GqlgenModels, "gqlgen_models", false, "Also generate "+gqlgenModelsFile+", a gqlgen.yml models section binding the schema types to the protobuf Go types")
+	flags.StringVar(&opts.GqlgenPackage, "gqlgen_package", "", "Go import path of the package "+gqlgenSupportFile+" is generated in, holding the scalar marshalers and union types the gqlgen models bind to")
+	flags.BoolVar(&opts.EnumDropUnspecified, "enum_drop_unspecified", false, "Leave the FOO_UNSPECIFIED zero value out of generated enums")
+}
@@ -0,0 +1,140 @@
+package generator
+
+import (
+	"fmt"
+	"log"
+	"path"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Output modes for the output_mode plugin option
+const (
+	// A schema file per service, named after its subgraph
+	OutputModePerService = "per_service"
+	// A schema file per proto file, for the services it declares
+	OutputModePerFile = "per_file"
+	// A schema file per proto package, for the services of its files
+	OutputModePerPackage = "per_package"
+	// A single schema file for every service
+	OutputModeMerged = "merged"
+)
+
+// mergedSchemaName is the name of the schema generated in merged mode
+const mergedSchemaName = "schema"
+
+// schemaGroup is the services rendered into a single schema file, which
+// share its types and schema root
+type schemaGroup struct {
+	// The name of the schema file, without extension
+	name string
+	// The name of the subgraph of the schema, empty for merged schemas as
+	// they don't serve a single subgraph
+	subgraph string
+	services []*protogen.Service
+	// The files declaring the services, in the order they are first seen
+	files []*protogen.File
+}
+
+// add adds svc, declared in file, to the group
+func (sg *schemaGroup) add(file *protogen.File, svc *protogen.Service) {
+	sg.services = append(sg.services, svc)
+	for _, f := range sg.files {
+		if f == file {
+			return
+		}
+	}
+	sg.files = append(sg.files, file)
+}
+
+// fileOf returns the file of the group declaring svc
+func (sg *schemaGroup) fileOf(svc *protogen.Service) *protogen.File {
+	for _, f := range sg.files {
+		if f.Desc == svc.Desc.ParentFile() {
+			return f
+		}
+	}
+	return nil
+}
+
+// schemaGroups returns the services schemas are generated for, grouped into
+// schema files according to the output_mode option. Services that aren't
+// federated are skipped when federated_only is set, and otherwise always
+// get a plain schema of their own, as they aren't part of the supergraph.
+func (g *Generator) schemaGroups(gen *protogen.Plugin) ([]*schemaGroup, error) {
+	var groups []*schemaGroup
+	byKey := make(map[string]*schemaGroup)
+	// Subgraphs can be renamed, so make sure no two groups share a file
+	names := make(map[string]string)
+	for _, f := range gen.Files {
+		if !f.Generate {
+			continue
+		}
+		for _, svc := range f.Services {
+			federated := isFederated(svc, g.opts.FederatedOnly)
+			if !federated && g.opts.FederatedOnly {
+				log.Printf("Skipping service %s: not federated", svc.Desc.FullName())
+				continue
+			}
+
+			var key, name string
+			switch {
+			case !federated:
+				key = string(svc.Desc.FullName())
+				name = subgraphName(svc)
+			case g.opts.OutputMode == OutputModePerFile:
+				key = f.Desc.Path()
+				name = string(f.Desc.Package()) + "." + strings.TrimSuffix(path.Base(f.Desc.Path()), ".proto")
+			case g.opts.OutputMode == OutputModePerPackage:
+				key = string(f.Desc.Package())
+				name = key
+			case g.opts.OutputMode == OutputModeMerged:
+				name = mergedSchemaName
+			default:
+				key = string(svc.Desc.FullName())
+				name = subgraphName(svc)
+			}
+
+			if group, ok := byKey[key]; ok {
+				group.add(f, svc)
+				continue
+			}
+			if other, ok := names[name]; ok {
+				if g.opts.OutputMode == "" || g.opts.OutputMode == OutputModePerService {
+					return nil, fmt.Errorf("services %s and %s both generate subgraph %q", other, svc.Desc.FullName(), name)
+				}
+				return nil, fmt.Errorf("%s and %s both generate schema %q", other, key, name)
+			}
+			names[name] = key
+
+			group := &schemaGroup{name: name, subgraph: name}
+			if federated && g.opts.OutputMode == OutputModeMerged {
+				group.subgraph = ""
+			}
+			group.add(f, svc)
+			byKey[key] = group
+			groups = append(groups, group)
+		}
+	}
+	return groups, nil
+}
+
+// checkOperationNames reports operations in a schema that have the same name,
+// as they would be the same root field. Names can collide across services, or
+// within a service once verbs are stripped or overridden with
+// (metadata.v1.operation_name).
+func checkOperationNames(services []*ServiceData) error {
+	owners := make(map[string]protoreflect.FullName)
+	for _, svc := range services {
+		for _, m := range svc.Methods {
+			key := m.Type + "." + m.Name
+			if other, ok := owners[key]; ok {
+				return fmt.Errorf("%s and %s both generate %s field %q, rename one with (metadata.v1.operation_name)", other, m.method.Desc.FullName(), m.Type, m.Name)
+			}
+			owners[key] = m.method.Desc.FullName()
+		}
+	}
+	return nil
+}
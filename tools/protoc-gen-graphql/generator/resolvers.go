@@ -0,0 +1,572 @@
+package generator
+
+import (
+	"fmt"
+	"log"
+	"path"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// Names of the plugins built from the generator: protoc-gen-graphql
+// generates the schemas, and protoc-gen-gqlgen-connect their gqlgen resolvers
+const (
+	schemaPluginName   = "protoc-gen-graphql"
+	resolverPluginName = "protoc-gen-gqlgen-connect"
+)
+
+const (
+	contextPackage = protogen.GoImportPath("context")
+	fmtPackage     = protogen.GoImportPath("fmt")
+	connectPackage = protogen.GoImportPath("connectrpc.com/connect")
+)
+
+// resolverService is a service resolvers are generated for, with the data
+// of the schema it is generated in and of the service in that schema
+type resolverService struct {
+	service *protogen.Service
+	file    *protogen.File
+	data    *TemplateData
+	schema  *ServiceData
+	// The Resolver field holding the service's Connect client
+	client string
+}
+
+// resolverGenerator generates gqlgen resolvers that implement the
+// operations and entities of the generated schemas by calling the services'
+// Connect clients
+type resolverGenerator struct {
+	gen      *protogen.Plugin
+	pkg      protogen.GoImportPath
+	model    protogen.GoImportPath
+	services []*resolverService
+	// The GraphQL types of the schemas, by name, and their custom scalars
+	types   map[string]graphQLKind
+	scalars []string
+	// The gqlgen models scalars are bound to instead of their defaults
+	scalarModels map[string]scalarModel
+	// The types converters are generated for
+	objects []*Message
+	inputs  []*InputType
+	enums   []*Enum
+	// The well-known types converted, and whether google.protobuf.Empty is
+	// converted from a Boolean
+	wellKnownTypes map[protoreflect.FullName]bool
+	useEmpty       bool
+}
+
+// GenerateResolvers processes protobuf files and generates gqlgen resolvers
+// for the GraphQL schemas Generate would produce for them: the root
+// Resolver holding one Connect client per service, a resolver per
+// operation and the federation entity resolvers.
+func (g *Generator) GenerateResolvers(gen *protogen.Plugin) error {
+	gen.SupportedFeatures = uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL)
+	if g.opts.ResolverPackage == "" {
+		return fmt.Errorf("resolver_package is required to generate resolvers")
+	}
+
+	rg := &resolverGenerator{
+		gen:            gen,
+		pkg:            protogen.GoImportPath(g.opts.ResolverPackage),
+		model:          protogen.GoImportPath(g.opts.ModelPackage),
+		types:          make(map[string]graphQLKind),
+		scalarModels:   g.scalarModels,
+		wellKnownTypes: make(map[protoreflect.FullName]bool),
+	}
+	if rg.model == "" {
+		rg.model = rg.pkg + "/model"
+	}
+
+	groups, err := g.schemaGroups(gen)
+	if err != nil {
+		return err
+	}
+	clients := make(map[string]bool)
+	for _, group := range groups {
+		data, err := prepareTemplateData(group, newTypeMapper(g))
+		if err != nil {
+			return err
+		}
+		rg.registerTypes(data)
+
+		for i, svc := range group.services {
+			// Services with the same name in different packages get
+			// qualified client fields
+			client := svc.GoName
+			if clients[client] {
+				client = qualifiedTypeName(svc.Desc)
+			}
+			clients[client] = true
+
+			rg.services = append(rg.services, &resolverService{
+				service: svc,
+				file:    group.fileOf(svc),
+				data:    data,
+				schema:  data.Services[i],
+				client:  client,
+			})
+		}
+	}
+
+	rg.generateRoot()
+	for _, svc := range rg.services {
+		rg.generateOperations(svc)
+	}
+	rg.generateEntities()
+	rg.generateConversions()
+	return rg.generateScalarModels()
+}
+
+// newFile creates a Go file in the resolver package
+func (rg *resolverGenerator) newFile(name string) *protogen.GeneratedFile {
+	gf := rg.gen.NewGeneratedFile(name, rg.pkg)
+	gf.P("// Code generated by ", resolverPluginName, ". DO NOT EDIT.")
+	gf.P()
+	gf.P("package ", path.Base(string(rg.pkg)))
+	gf.P()
+	return gf
+}
+
+// clientInterface returns the Connect client interface generated for svc
+func clientInterface(file *protogen.File, svc *protogen.Service) protogen.GoIdent {
+	connectPath := file.GoImportPath + protogen.GoImportPath("/"+string(file.GoPackageName)+"connect")
+	return connectPath.Ident(svc.GoName + "Client")
+}
+
+// operationResolvers are the gqlgen resolver types of the root operation
+// types, in the order they are declared
+var operationResolvers = []struct {
+	Type     OperationType
+	Receiver string
+}{
+	{OperationQuery, "queryResolver"},
+	{OperationMutation, "mutationResolver"},
+	{OperationSubscription, "subscriptionResolver"},
+}
+
+// generateRoot generates resolver.go: the Resolver struct with the Connect
+// clients and the resolver types of the operation types used
+func (rg *resolverGenerator) generateRoot() {
+	gf := rg.newFile("resolver.go")
+
+	gf.P("// Resolver is the root resolver, resolving operations and entities by")
+	gf.P("// calling the Connect clients of the services")
+	gf.P("type Resolver struct {")
+	for _, svc := range rg.services {
+		gf.P(svc.client, " ", clientInterface(svc.file, svc.service))
+	}
+	gf.P("}")
+	gf.P()
+
+	var params, fields []string
+	for _, svc := range rg.services {
+		param := goPrivateName(svc.client)
+		params = append(params, param+" "+gf.QualifiedGoIdent(clientInterface(svc.file, svc.service)))
+		fields = append(fields, svc.client+": "+param+",")
+	}
+	gf.P("// NewResolver creates a root resolver calling the given clients")
+	gf.P("func NewResolver(", strings.Join(params, ", "), ") *Resolver {")
+	gf.P("return &Resolver{")
+	for _, field := range fields {
+		gf.P(field)
+	}
+	gf.P("}")
+	gf.P("}")
+
+	for _, op := range operationResolvers {
+		if !rg.hasOperation(op.Type) {
+			continue
+		}
+		gf.P()
+		gf.P("// ", op.Type, " returns ", op.Type, "Resolver implementation.")
+		gf.P("func (r *Resolver) ", op.Type, "() ", op.Type, "Resolver { return &", op.Receiver, "{r} }")
+		gf.P()
+		gf.P("type ", op.Receiver, " struct{ *Resolver }")
+	}
+	if len(rg.entities()) > 0 {
+		gf.P()
+		gf.P("// Entity returns EntityResolver implementation.")
+		gf.P("func (r *Resolver) Entity() EntityResolver { return &entityResolver{r} }")
+		gf.P()
+		gf.P("type entityResolver struct{ *Resolver }")
+	}
+}
+
+// hasOperation reports whether any service has an operation of the type
+func (rg *resolverGenerator) hasOperation(opType OperationType) bool {
+	for _, svc := range rg.services {
+		if hasMethodType(svc.schema.Methods, opType) {
+			return true
+		}
+	}
+	return false
+}
+
+// generateOperations generates <subgraph>.resolvers.go with the resolvers
+// of the operations of svc
+func (rg *resolverGenerator) generateOperations(svc *resolverService) {
+	methods := svc.schema.Methods
+	if len(methods) == 0 {
+		return
+	}
+	gf := rg.newFile(svc.schema.Name + ".resolvers.go")
+	for i, m := range methods {
+		if i > 0 {
+			gf.P()
+		}
+		rg.generateOperation(gf, svc, m)
+	}
+}
+
+// generateOperation generates the resolver of the operation of method m,
+// which builds the request from the arguments, calls the method and
+// converts its response to the operation's result
+func (rg *resolverGenerator) generateOperation(gf *protogen.GeneratedFile, svc *resolverService, m *Method) {
+	receiver := "queryResolver"
+	for _, op := range operationResolvers {
+		if string(op.Type) == m.Type {
+			receiver = op.Receiver
+		}
+	}
+
+	params := []string{"ctx " + gf.QualifiedGoIdent(contextPackage.Ident("Context"))}
+	for _, arg := range m.Args {
+		params = append(params, goPrivateName(arg.Name)+" "+rg.goType(gf, arg, true))
+	}
+	result := rg.goType(gf, rg.outputField(m), false)
+	if m.Type == string(OperationSubscription) {
+		result = "<-chan " + result
+	}
+
+	name := goName(m.Name)
+	gf.P("// ", name, " is the resolver for the ", m.Name, " field.")
+	gf.P("func (r *", receiver, ") ", name, "(", strings.Join(params, ", "), ") (", result, ", error) {")
+	rg.generateRequest(gf, m)
+
+	call := "r." + svc.client + "." + m.method.GoName + "(ctx, " + gf.QualifiedGoIdent(connectPackage.Ident("NewRequest")) + "(req))"
+	switch {
+	case m.method.Desc.IsStreamingServer() && m.Type == string(OperationSubscription):
+		rg.generateStream(gf, m, call)
+		return
+	case m.method.Desc.IsStreamingServer():
+		// Queries and mutations of server-streaming methods return the
+		// first response
+		gf.P("stream, err := ", call)
+		gf.P("if err != nil {")
+		gf.P("return nil, err")
+		gf.P("}")
+		gf.P("defer stream.Close()")
+		gf.P("if !stream.Receive() {")
+		gf.P("return nil, stream.Err()")
+		gf.P("}")
+		gf.P("msg := stream.Msg()")
+	default:
+		gf.P("resp, err := ", call)
+		gf.P("if err != nil {")
+		gf.P("return nil, err")
+		gf.P("}")
+		gf.P("msg := resp.Msg")
+	}
+
+	out := rg.generateOutput(gf, m)
+	if m.Type == string(OperationSubscription) {
+		// A subscription to a unary method yields its single response
+		gf.P("ch := make(chan ", rg.goType(gf, rg.outputField(m), false), ", 1)")
+		gf.P("ch <- ", out)
+		gf.P("close(ch)")
+		out = "ch"
+	}
+	gf.P("return ", out, ", nil")
+	gf.P("}")
+}
+
+// generateStream generates the body of a subscription to a server-streaming
+// method, sending every response on the returned channel until the stream
+// ends or the subscription is cancelled
+func (rg *resolverGenerator) generateStream(gf *protogen.GeneratedFile, m *Method, call string) {
+	gf.P("stream, err := ", call)
+	gf.P("if err != nil {")
+	gf.P("return nil, err")
+	gf.P("}")
+	gf.P("ch := make(chan ", rg.goType(gf, rg.outputField(m), false), ")")
+	gf.P("go func() {")
+	gf.P("defer close(ch)")
+	gf.P("defer stream.Close()")
+	gf.P("for stream.Receive() {")
+	gf.P("msg := stream.Msg()")
+	out := rg.generateOutput(gf, m)
+	gf.P("select {")
+	gf.P("case ch <- ", out, ":")
+	gf.P("case <-ctx.Done():")
+	gf.P("return")
+	gf.P("}")
+	gf.P("}")
+	gf.P("}()")
+	gf.P("return ch, nil")
+	gf.P("}")
+}
+
+// generateRequest generates the statements building req, the request of
+// m, from the resolver's arguments
+func (rg *resolverGenerator) generateRequest(gf *protogen.GeneratedFile, m *Method) {
+	args := m.Args
+	if len(args) > 0 && args[0].input != nil {
+		// Non-null input objects are passed by value
+		gf.P("req := ", goName(args[0].input.Name), "ToProto(&", goPrivateName(args[0].Name), ")")
+		args = args[1:]
+	} else {
+		gf.P("req := &", m.method.Input.GoIdent, "{}")
+	}
+
+	for _, arg := range args {
+		src := goPrivateName(arg.Name)
+		if rg.types[arg.GraphQLType] == inputKind && arg.NonNull && !arg.List && arg.Map == nil {
+			src = "&" + src
+		}
+		if !rg.toProtoField(gf, arg, src, "req") {
+			log.Printf("Warning: argument %s of %s can't be converted", arg.Name, m.method.Desc.FullName())
+		}
+	}
+}
+
+// outputField describes the result of the operation of m as a field, to
+// find its Go type
+func (rg *resolverGenerator) outputField(m *Method) *Field {
+	if m.payload != nil {
+		out := *m.payload
+		out.NonNull = false
+		return &out
+	}
+	return &Field{GraphQLType: m.OutputType}
+}
+
+// generateOutput generates the statements converting msg, the response of
+// m, to the operation's result and returns the result's expression
+func (rg *resolverGenerator) generateOutput(gf *protogen.GeneratedFile, m *Method) string {
+	switch {
+	case m.Pagination != nil:
+		return rg.generateConnection(gf, m)
+	case m.payload != nil:
+		out := rg.outputField(m)
+		gf.P("var out ", rg.goType(gf, out, false))
+		if !rg.fromProtoField(gf, out, "msg", "out") {
+			log.Printf("Warning: %s returns a %s, which can't be converted", m.method.Desc.FullName(), m.OutputType)
+		}
+		return "out"
+	case m.method.Output.Desc.FullName() == "google.protobuf.Empty":
+		return "ptr(true)"
+	case isWellKnownType(m.method.Output):
+		log.Printf("Warning: %s returns a %s, which can't be converted", m.method.Desc.FullName(), m.OutputType)
+		return "nil"
+	}
+	return goName(m.OutputType) + "FromProto(msg)"
+}
+
+// generateConnection generates the statements converting msg, the response
+// of the paginated method m, to a Relay connection. The next page token is
+// the end cursor and the cursor of the last edge, and the page token the
+// method was called with the start cursor. Page tokens only mark the end of
+// a page, so the other edges have no cursor.
+func (rg *resolverGenerator) generateConnection(gf *protogen.GeneratedFile, m *Method) string {
+	p := m.Pagination
+	next := "msg." + fieldGoName(m.method.Output, p.NextPageTokenField)
+	items := "msg." + p.items.GoName
+
+	gf.P("out := &", rg.model.Ident(goName(p.Connection.Name)), "{")
+	gf.P("PageInfo: &", rg.model.Ident("PageInfo"), "{")
+	gf.P("HasNextPage: ", next, ` != "",`)
+	gf.P(`HasPreviousPage: after != nil && *after != "",`)
+	gf.P("StartCursor: after,")
+	gf.P("},")
+	gf.P("}")
+	gf.P("if ", next, ` != "" {`)
+	gf.P("out.PageInfo.EndCursor = ptr(", next, ")")
+	gf.P("}")
+	if p.totalSize != nil {
+		totalCount := &Field{Name: "totalCount", GraphQLType: "Int", field: p.totalSize}
+		if !rg.fromProtoField(gf, totalCount, "msg", "out.TotalCount") {
+			log.Printf("Warning: the total size of %s can't be converted", m.method.Desc.FullName())
+		}
+	}
+
+	node, ok := rg.fromProtoValue(p.items, p.Connection.NodeType, "v")
+	if !ok {
+		log.Printf("Warning: the items of %s can't be converted", m.method.Desc.FullName())
+		return "out"
+	}
+	gf.P("for i, v := range ", items, " {")
+	gf.P("edge := &", rg.model.Ident(goName(p.Connection.EdgeName)), "{Node: ", node, "}")
+	gf.P("if i == len(", items, ")-1 && ", next, ` != "" {`)
+	gf.P("edge.Cursor = ptr(", next, ")")
+	gf.P("}")
+	gf.P("out.Edges = append(out.Edges, edge)")
+	gf.P("}")
+	return "out"
+}
+
+// fieldGoName returns the Go name of the field of msg with the proto name
+func fieldGoName(msg *protogen.Message, name string) string {
+	for _, f := range msg.Fields {
+		if string(f.Desc.Name()) == name {
+			return f.GoName
+		}
+	}
+	return name
+}
+
+// client returns the Resolver field holding the Connect client of svc
+func (rg *resolverGenerator) client(svc *protogen.Service) string {
+	for _, rs := range rg.services {
+		if rs.service == svc {
+			return rs.client
+		}
+	}
+	return ""
+}
+
+// resolverEntity is an entity resolvers are generated for, with the
+// reference methods of the schema resolving it
+type resolverEntity struct {
+	message *Message
+}
+
+// entities returns the entities of the federated services, each once,
+// resolved by the schema declaring their reference methods
+func (rg *resolverGenerator) entities() []*resolverEntity {
+	var entities []*resolverEntity
+	seen := make(map[string]*resolverEntity)
+	for _, svc := range rg.services {
+		if !svc.data.Federated {
+			continue
+		}
+		for _, msg := range svc.data.Messages {
+			if !msg.Entity || !hasResolvableKey(msg) {
+				continue
+			}
+			if entity, ok := seen[msg.Name]; ok {
+				if len(entity.message.ReferenceMethods) == 0 && len(msg.ReferenceMethods) > 0 {
+					entity.message = msg
+				}
+				continue
+			}
+			entity := &resolverEntity{message: msg}
+			seen[msg.Name] = entity
+			entities = append(entities, entity)
+		}
+	}
+	return entities
+}
+
+// hasResolvableKey reports whether the entity msg has a key the subgraph
+// resolves it by
+func hasResolvableKey(msg *Message) bool {
+	for _, key := range msg.Keys {
+		if key.Resolvable {
+			return true
+		}
+	}
+	return false
+}
+
+// generateEntities generates entity.resolvers.go with a FindXByY resolver
+// for every resolvable key of the entities. Each calls the first of the
+// entity's reference methods whose request has all the key fields.
+func (rg *resolverGenerator) generateEntities() {
+	entities := rg.entities()
+	if len(entities) == 0 {
+		return
+	}
+
+	gf := rg.newFile("entity.resolvers.go")
+	first := true
+	for _, entity := range entities {
+		msg := entity.message
+		for _, key := range msg.Keys {
+			if !key.Resolvable {
+				continue
+			}
+			fields, ok := keyFields(msg, key)
+			if !ok {
+				log.Printf("Warning: no resolver is generated for %s by its nested key %q", msg.Name, key.Fields)
+				continue
+			}
+
+			if !first {
+				gf.P()
+			}
+			first = false
+
+			var names, params []string
+			for _, f := range fields {
+				names = append(names, goName(f.Name))
+				params = append(params, goPrivateName(f.Name)+" "+rg.goType(gf, f, true))
+			}
+			resolver := "find" + goName(msg.Name) + "By" + strings.Join(names, "And")
+			gf.P("// ", goName(resolver), " is the resolver for the ", resolver, " field.")
+			gf.P("func (r *entityResolver) ", goName(resolver), "(ctx ", contextPackage.Ident("Context"), ", ", strings.Join(params, ", "), ") (*", rg.model.Ident(goName(msg.Name)), ", error) {")
+			rg.generateEntityLookup(gf, entity, key, fields)
+			gf.P("}")
+		}
+	}
+}
+
+// keyFields returns the fields of the entity msg making up key, or false if
+// the key selects nested fields
+func keyFields(msg *Message, key *Key) ([]*Field, bool) {
+	if strings.Contains(key.Fields, "{") {
+		return nil, false
+	}
+	var fields []*Field
+	for _, name := range strings.Fields(key.Fields) {
+		for _, f := range msg.Fields {
+			if f.Name == name && f.field != nil {
+				fields = append(fields, f)
+			}
+		}
+	}
+	return fields, len(fields) > 0
+}
+
+// generateEntityLookup generates the body of an entity resolver, calling
+// the entity's reference method with the key fields
+func (rg *resolverGenerator) generateEntityLookup(gf *protogen.GeneratedFile, entity *resolverEntity, key *Key, fields []*Field) {
+	msg := entity.message
+	for _, m := range msg.ReferenceMethods {
+		if m.OutputType != msg.Name || m.Pagination != nil || m.method.Desc.IsStreamingServer() {
+			continue
+		}
+
+		// The request takes the key fields under their proto names
+		var args []*Field
+		for _, f := range fields {
+			for _, reqField := range m.method.Input.Fields {
+				if reqField.Desc.Name() == f.field.Desc.Name() && reqField.Desc.Kind() == f.field.Desc.Kind() {
+					arg := *f
+					arg.field = reqField
+					args = append(args, &arg)
+				}
+			}
+		}
+		if len(args) != len(fields) {
+			continue
+		}
+
+		gf.P("req := &", m.method.Input.GoIdent, "{}")
+		for _, arg := range args {
+			rg.toProtoField(gf, arg, goPrivateName(arg.Name), "req")
+		}
+		gf.P("resp, err := r.", rg.client(m.method.Parent), ".", m.method.GoName, "(ctx, ", connectPackage.Ident("NewRequest"), "(req))")
+		gf.P("if err != nil {")
+		gf.P("return nil, err")
+		gf.P("}")
+		gf.P("msg := resp.Msg")
+		gf.P("return ", rg.generateOutput(gf, m), ", nil")
+		return
+	}
+
+	log.Printf("Warning: no method provides %s by %q, its entity resolver returns an error", msg.Name, key.Fields)
+	gf.P("return nil, ", fmtPackage.Ident("Errorf"), "(", fmt.Sprintf("%q", fmt.Sprintf("no method resolves %s by %s", msg.Name, key.Fields)), ")")
+}
@@ -0,0 +1,72 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// defaultScalars maps protobuf scalar kinds to GraphQL scalar types.
+// GraphQL's Int is a signed 32-bit integer, so unsigned 32-bit and 64-bit
+// integers use custom scalars that are serialized as strings.
+var defaultScalars = map[protoreflect.Kind]string{
+	protoreflect.DoubleKind:   "Float",
+	protoreflect.FloatKind:    "Float",
+	protoreflect.Int32Kind:    "Int",
+	protoreflect.Sint32Kind:   "Int",
+	protoreflect.Sfixed32Kind: "Int",
+	protoreflect.Uint32Kind:   "Int64",
+	protoreflect.Fixed32Kind:  "Int64",
+	protoreflect.Int64Kind:    "Int64",
+	protoreflect.Sint64Kind:   "Int64",
+	protoreflect.Sfixed64Kind: "Int64",
+	protoreflect.Uint64Kind:   "UInt64",
+	protoreflect.Fixed64Kind:  "UInt64",
+	protoreflect.BoolKind:     "Boolean",
+	protoreflect.StringKind:   "String",
+	protoreflect.BytesKind:    "Base64",
+}
+
+// builtinScalars are the scalars every GraphQL schema provides
+var builtinScalars = map[string]bool{
+	"Int":     true,
+	"Float":   true,
+	"String":  true,
+	"Boolean": true,
+	"ID":      true,
+}
+
+// scalarDescriptions documents the custom scalars declared by the generator
+var scalarDescriptions = map[string]string{
+	"Int64":  "A signed 64-bit integer, serialized as a string to avoid precision loss.",
+	"UInt64": "An unsigned 64-bit integer, serialized as a string to avoid precision loss.",
+	"Base64": "Binary data, serialized as a standard base64 encoded string.",
+
+	"DateTime":  "A point in time, serialized as an RFC 3339 string (e.g. 2006-01-02T15:04:05Z).",
+	"Duration":  "A span of time, serialized as seconds with an s suffix (e.g. 1.5s).",
+	"JSON":      "An arbitrary JSON value.",
+	"FieldMask": "A set of field paths, serialized as a comma separated string (e.g. name,price).",
+}
+
+// Scalar is a custom scalar declared in the generated schema
+type Scalar struct {
+	Name    string
+	Comment string
+}
+
+// parseScalarOverride parses a "kind:Type" scalar mapping override, as passed
+// with the scalar plugin option (e.g. scalar=int64:String)
+func parseScalarOverride(overrides map[protoreflect.Kind]string, value string) error {
+	kindName, gqlType, ok := strings.Cut(value, ":")
+	if !ok || kindName == "" || gqlType == "" {
+		return fmt.Errorf("invalid scalar mapping %q, expected kind:Type", value)
+	}
+	for kind := range defaultScalars {
+		if kind.String() == kindName {
+			overrides[kind] = gqlType
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid scalar mapping %q, unknown protobuf type %q", value, kindName)
+}
@@ -0,0 +1,22 @@
+package generator
+
+import "google.golang.org/protobuf/compiler/protogen"
+
+// isFederated reports whether svc is part of the federated graph. Services
+// are federated unless they set (metadata.v1.federated) = false or, when
+// federatedOnly is set, unless they set it to true.
+func isFederated(svc *protogen.Service, federatedOnly bool) bool {
+	if federated := serviceAnnotations(svc).Federated; federated != nil {
+		return *federated
+	}
+	return !federatedOnly
+}
+
+// subgraphName returns the name of the subgraph served by svc: its
+// (metadata.v1.service_name), or its full proto name if that isn't set
+func subgraphName(svc *protogen.Service) string {
+	if name := serviceAnnotations(svc).ServiceName; name != "" {
+		return name
+	}
+	return string(svc.Desc.FullName())
+}
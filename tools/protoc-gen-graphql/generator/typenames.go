@@ -0,0 +1,342 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Collision policies for the type_collisions plugin option
+const (
+	// Colliding types are qualified with their package (common.v1.Money
+	// becomes CommonV1Money), except one from the package being generated
+	TypeCollisionsPrefix = "prefix"
+	// Colliding types fail the generation
+	TypeCollisionsError = "error"
+)
+
+// pageInfoType is the name of the type holding the page info of
+// connections, declared by every subgraph with paginated methods
+const pageInfoType = "PageInfo"
+
+// namedType is a message or enum that needs a GraphQL type name
+type namedType struct {
+	desc protoreflect.Descriptor
+	// Whether the name was set with (metadata.v1.type_name) or
+	// (metadata.v1.enum_name)
+	explicit bool
+	name     string
+}
+
+// resolveTypeNames names the GraphQL types of the messages and enums of
+// files and of every type they refer to, following fields across files and
+// packages. Types are named after their message or enum unless renamed with
+// an option; types whose names collide, or take the name of PageInfo in
+// schemas with connections, are handled according to the type_collisions
+// option. The types generated for the messages' oneofs and maps are named
+// next.
+func (tm *typeMapper) resolveTypeNames(files []*protogen.File) error {
+	var types []*namedType
+	seen := make(map[protoreflect.FullName]bool)
+
+	var addMessage func(msg *protogen.Message)
+	addEnum := func(e *protogen.Enum) {
+		if seen[e.Desc.FullName()] {
+			return
+		}
+		seen[e.Desc.FullName()] = true
+		name := enumAnnotations(e).Name
+		types = append(types, &namedType{desc: e.Desc, explicit: name != "", name: name})
+	}
+	addMessage = func(msg *protogen.Message) {
+		if seen[msg.Desc.FullName()] || isWellKnownType(msg) {
+			return
+		}
+		seen[msg.Desc.FullName()] = true
+		// Map entries are named after the field holding them
+		if !msg.Desc.IsMapEntry() {
+			name := messageAnnotations(msg).TypeName
+			types = append(types, &namedType{desc: msg.Desc, explicit: name != "", name: name})
+		}
+		for _, f := range msg.Fields {
+			if f.Message != nil {
+				addMessage(f.Message)
+			}
+			if f.Enum != nil {
+				addEnum(f.Enum)
+			}
+		}
+	}
+
+	// Every type declared in the files, then the request and response types
+	// of their services, which may live in other files
+	var addDeclared func(messages []*protogen.Message)
+	addDeclared = func(messages []*protogen.Message) {
+		for _, msg := range messages {
+			addMessage(msg)
+			for _, e := range msg.Enums {
+				addEnum(e)
+			}
+			addDeclared(msg.Messages)
+		}
+	}
+	for _, file := range files {
+		addDeclared(file.Messages)
+		for _, e := range file.Enums {
+			addEnum(e)
+		}
+	}
+	reserved := make(map[string]bool)
+	for _, file := range files {
+		for _, svc := range file.Services {
+			for _, method := range svc.Methods {
+				addMessage(method.Input)
+				addMessage(method.Output)
+				if paginatedList(method) != nil {
+					reserved[pageInfoType] = true
+				}
+			}
+		}
+	}
+
+	// Types of the files' package are preferred when names collide, unless
+	// the files are from several packages
+	pkg := files[0].Desc.Package()
+	for _, file := range files {
+		if file.Desc.Package() != pkg {
+			pkg = ""
+		}
+	}
+
+	// Group the types by the name they would have on their own
+	var names []string
+	groups := make(map[string][]*namedType)
+	for _, t := range types {
+		if !t.explicit {
+			t.name = defaultTypeName(t.desc)
+		}
+		if _, ok := groups[t.name]; !ok {
+			names = append(names, t.name)
+		}
+		groups[t.name] = append(groups[t.name], t)
+	}
+
+	for _, name := range names {
+		group := groups[name]
+		switch {
+		case reserved[name]:
+			if err := tm.qualifyReservedTypeNames(group); err != nil {
+				return err
+			}
+		case len(group) > 1:
+			if err := tm.qualifyTypeNames(group, pkg); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Qualified names may still collide with other types
+	owners := make(map[string]protoreflect.Descriptor)
+	for _, t := range types {
+		if owner, ok := owners[t.name]; ok {
+			return fmt.Errorf("%s and %s both generate GraphQL type %q, rename one with (metadata.v1.type_name) or (metadata.v1.enum_name)", owner.FullName(), t.desc.FullName(), t.name)
+		}
+		owners[t.name] = t.desc
+		tm.names[t.desc.FullName()] = t.name
+		tm.typeNames[t.name] = true
+	}
+	for name := range reserved {
+		tm.typeNames[name] = true
+	}
+
+	for _, t := range types {
+		if msg, ok := t.desc.(protoreflect.MessageDescriptor); ok {
+			if err := tm.resolveGeneratedNames(msg, t.name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// qualifyReservedTypeNames qualifies the types taking a name the generator
+// declares itself with their package, as they can't be renamed
+func (tm *typeMapper) qualifyReservedTypeNames(group []*namedType) error {
+	for _, t := range group {
+		switch {
+		case t.explicit:
+			return fmt.Errorf("%s is renamed to GraphQL type %q, which is reserved for the page info of connections", t.desc.FullName(), t.name)
+		case tm.opts.TypeCollisions == TypeCollisionsError:
+			return fmt.Errorf("%s generates GraphQL type %q, which is reserved for the page info of connections, rename it with (metadata.v1.type_name) or (metadata.v1.enum_name), or set type_collisions=%s", t.desc.FullName(), t.name, TypeCollisionsPrefix)
+		}
+		t.name = qualifiedTypeName(t.desc)
+	}
+	return nil
+}
+
+// resolveGeneratedNames names the types generated for the oneofs and map
+// fields of msg, whose type is named name: the union of each oneof and the
+// wrapper objects of its members, and the entry type of each map. Messages
+// and enums keep their names when a generated type would take them; under
+// the prefix policy the generated type is qualified with the package of msg
+// instead (Payment.method becomes ShopV1PaymentMethod when a PaymentMethod
+// message exists).
+func (tm *typeMapper) resolveGeneratedNames(msg protoreflect.MessageDescriptor, name string) error {
+	qualified := qualifiedTypeName(msg)
+	fields := msg.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		if f := fields.Get(i); f.IsMap() {
+			suffix := string(f.Message().Name())
+			if err := tm.nameGeneratedType(f.Message(), name+suffix, qualified+suffix); err != nil {
+				return err
+			}
+		}
+	}
+
+	oneofs := msg.Oneofs()
+	for i := 0; i < oneofs.Len(); i++ {
+		o := oneofs.Get(i)
+		if o.IsSynthetic() {
+			continue
+		}
+		suffix := camelCase(string(o.Name()))
+		if err := tm.nameGeneratedType(o, name+suffix, qualified+suffix); err != nil {
+			return err
+		}
+
+		// Members are wrapped as in oneof: scalars, enums, well-known
+		// types and repeated message types
+		seen := make(map[string]bool)
+		members := o.Fields()
+		for j := 0; j < members.Len(); j++ {
+			f := members.Get(j)
+			if m := f.Message(); m != nil && !isWellKnownName(m.FullName()) && !seen[tm.typeName(m)] {
+				seen[tm.typeName(m)] = true
+				continue
+			}
+			suffix := camelCase(string(f.Name()))
+			if err := tm.nameGeneratedType(f, name+suffix, qualified+suffix); err != nil {
+				return err
+			}
+			seen[tm.names[f.FullName()]] = true
+		}
+	}
+	return nil
+}
+
+// nameGeneratedType names the type generated for desc, a oneof, oneof member
+// or map entry, name, or qualified when name is taken
+func (tm *typeMapper) nameGeneratedType(desc protoreflect.Descriptor, name, qualified string) error {
+	if tm.typeNames[name] {
+		if tm.opts.TypeCollisions == TypeCollisionsError {
+			return fmt.Errorf("%s generates GraphQL type %q, which is already taken, rename the type with (metadata.v1.type_name) or set type_collisions=%s", desc.FullName(), name, TypeCollisionsPrefix)
+		}
+		if tm.typeNames[qualified] {
+			return fmt.Errorf("%s generates GraphQL type %q, which is already taken, as is %q; rename the type with (metadata.v1.type_name)", desc.FullName(), name, qualified)
+		}
+		name = qualified
+	}
+	tm.names[desc.FullName()] = name
+	tm.typeNames[name] = true
+	return nil
+}
+
+// qualifyTypeNames resolves a group of types sharing a name. Under the
+// prefix policy a type renamed with an option keeps its name, or else the
+// one type from pkg if there is only one; the others are qualified with
+// their package.
+func (tm *typeMapper) qualifyTypeNames(group []*namedType, pkg protoreflect.FullName) error {
+	if tm.opts.TypeCollisions == TypeCollisionsError {
+		return fmt.Errorf("%s and %s both generate GraphQL type %q, rename one with (metadata.v1.type_name) or (metadata.v1.enum_name), or set type_collisions=%s", group[0].desc.FullName(), group[1].desc.FullName(), group[0].name, TypeCollisionsPrefix)
+	}
+
+	var keep *namedType
+	local := 0
+	for _, t := range group {
+		if t.explicit {
+			if keep != nil && keep.explicit {
+				return fmt.Errorf("%s and %s are both renamed to GraphQL type %q", keep.desc.FullName(), t.desc.FullName(), t.name)
+			}
+			keep = t
+		}
+	}
+	if keep == nil {
+		for _, t := range group {
+			if t.desc.ParentFile().Package() == pkg {
+				keep = t
+				local++
+			}
+		}
+		if local > 1 {
+			keep = nil
+		}
+	}
+
+	for _, t := range group {
+		if t != keep {
+			t.name = qualifiedTypeName(t.desc)
+		}
+	}
+	return nil
+}
+
+// defaultTypeName returns the GraphQL name of a message or enum that isn't
+// renamed or colliding. Nested enums are prefixed with the names of their
+// enclosing messages (e.g. Product_Status) so that enums with the same name
+// in different messages don't collide.
+func defaultTypeName(desc protoreflect.Descriptor) string {
+	name := string(desc.Name())
+	if _, ok := desc.(protoreflect.EnumDescriptor); !ok {
+		return name
+	}
+	for parent := desc.Parent(); parent != nil; parent = parent.Parent() {
+		msg, ok := parent.(protoreflect.MessageDescriptor)
+		if !ok {
+			break
+		}
+		name = string(msg.Name()) + "_" + name
+	}
+	return name
+}
+
+// qualifiedTypeName returns the name of a message or enum prefixed with its
+// package and enclosing messages (common.v1.Money becomes CommonV1Money and
+// shop.v1.Product.Variant ShopV1ProductVariant)
+func qualifiedTypeName(desc protoreflect.Descriptor) string {
+	var prefix strings.Builder
+	for _, part := range strings.Split(string(desc.ParentFile().Package()), ".") {
+		prefix.WriteString(camelCase(part))
+	}
+	if _, ok := desc.(protoreflect.EnumDescriptor); ok {
+		return prefix.String() + defaultTypeName(desc)
+	}
+
+	name := string(desc.Name())
+	for parent := desc.Parent(); parent != nil; parent = parent.Parent() {
+		msg, ok := parent.(protoreflect.MessageDescriptor)
+		if !ok {
+			break
+		}
+		name = string(msg.Name()) + name
+	}
+	return prefix.String() + name
+}
+
+// generatedTypeName returns the GraphQL name resolved for the type
+// generated for a oneof, oneof member or map entry, or name if it has none
+func (tm *typeMapper) generatedTypeName(desc protoreflect.Descriptor, name string) string {
+	if resolved, ok := tm.names[desc.FullName()]; ok {
+		return resolved
+	}
+	return name
+}
+
+// typeName returns the GraphQL name resolved for a message or enum
+func (tm *typeMapper) typeName(desc protoreflect.Descriptor) string {
+	if name, ok := tm.names[desc.FullName()]; ok {
+		return name
+	}
+	return defaultTypeName(desc)
+}
@@ -0,0 +1,202 @@
+package generator
+
+import (
+	"sort"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// typeMapper maps protobuf fields to GraphQL types and records the custom
+// scalars, directives, object types, enums, map entries, oneofs, input types
+// and connections used along the way so they can be declared in the schema
+type typeMapper struct {
+	names           map[protoreflect.FullName]string
+	objects         []*protogen.Message
+	seenObjects     map[protoreflect.FullName]bool
+	scalars         map[protoreflect.Kind]string
+	used            map[string]bool
+	enums           []*protogen.Enum
+	seenEnums       map[protoreflect.FullName]bool
+	mapEntries      []*MapEntry
+	seenMapEntries  map[protoreflect.FullName]*MapEntry
+	oneofs          []*Oneof
+	seenOneofs      map[protoreflect.FullName]*Oneof
+	inputs          []*InputType
+	seenInputs      map[protoreflect.FullName]*InputType
+	updateInputs    map[protoreflect.FullName]*InputType
+	connections     []*Connection
+	seenConnections map[string]*Connection
+	pageFields      map[protoreflect.FullName]bool
+	typeNames       map[string]bool
+	directives      map[string]bool
+	opts            Options
+}
+
+// newTypeMapper creates a typeMapper using the default scalar mapping with
+// the generator's overrides applied
+func newTypeMapper(g *Generator) *typeMapper {
+	tm := &typeMapper{
+		names:           make(map[protoreflect.FullName]string),
+		seenObjects:     make(map[protoreflect.FullName]bool),
+		scalars:         make(map[protoreflect.Kind]string, len(defaultScalars)),
+		used:            make(map[string]bool),
+		seenEnums:       make(map[protoreflect.FullName]bool),
+		seenMapEntries:  make(map[protoreflect.FullName]*MapEntry),
+		seenOneofs:      make(map[protoreflect.FullName]*Oneof),
+		seenInputs:      make(map[protoreflect.FullName]*InputType),
+		updateInputs:    make(map[protoreflect.FullName]*InputType),
+		seenConnections: make(map[string]*Connection),
+		pageFields:      make(map[protoreflect.FullName]bool),
+		typeNames:       make(map[string]bool),
+		directives:      make(map[string]bool),
+		opts:            g.opts,
+	}
+	for kind, name := range defaultScalars {
+		tm.scalars[kind] = name
+	}
+	for kind, name := range g.scalars {
+		tm.scalars[kind] = name
+	}
+	return tm
+}
+
+// newField creates a Field describing the GraphQL type of f where it is
+// used. Input fields refer to the input variants of generated types.
+func (tm *typeMapper) newField(f *protogen.Field, use typeUse) *Field {
+	field := &Field{
+		Name:        fieldName(f, tm.opts.Naming),
+		ProtoName:   string(f.Desc.Name()),
+		GraphQLType: tm.graphQLType(f, use),
+		NonNull:     isNonNull(f, use, tm.opts.EnumDropUnspecified),
+		List:        f.Desc.IsList(),
+		field:       f,
+	}
+	if f.Desc.IsMap() {
+		field.Map = tm.seenMapEntries[f.Message.Desc.FullName()]
+	}
+	return field
+}
+
+// graphQLType returns the GraphQL type name for the field, without any list
+// or non-null markers. Map fields are typed as their generated entry type,
+// whose values always use the plain input types.
+func (tm *typeMapper) graphQLType(f *protogen.Field, use typeUse) string {
+	if f.Desc.IsMap() && f.Message != nil {
+		entry := tm.mapEntry(f)
+		value := f.Message.Fields[1]
+		if use != outputUse {
+			if !entry.Input {
+				entry.Input = true
+				entry.InputValueType = tm.graphQLType(value, inputUse)
+			}
+			return entry.Name + "Input"
+		}
+		if !entry.Output {
+			entry.Output = true
+			entry.ValueType = tm.graphQLType(value, outputUse)
+		}
+		return entry.Name
+	}
+
+	kind := f.Desc.Kind()
+	if (kind == protoreflect.MessageKind || kind == protoreflect.GroupKind) && f.Message != nil {
+		if use != outputUse && !isWellKnownType(f.Message) {
+			return tm.inputType(f.Message, use == updateInputUse).Name
+		}
+		return tm.messageType(f.Message)
+	}
+	if kind == protoreflect.EnumKind && f.Enum != nil {
+		if !tm.seenEnums[f.Enum.Desc.FullName()] {
+			tm.seenEnums[f.Enum.Desc.FullName()] = true
+			tm.enums = append(tm.enums, f.Enum)
+		}
+		return tm.typeName(f.Enum.Desc)
+	}
+
+	return tm.scalarType(kind)
+}
+
+// scalarType returns the GraphQL scalar for a protobuf scalar kind
+func (tm *typeMapper) scalarType(kind protoreflect.Kind) string {
+	name, ok := tm.scalars[kind]
+	if !ok {
+		name = "String"
+	}
+	tm.useScalar(name)
+	return name
+}
+
+// messageType returns the GraphQL type name for a message, registering its
+// object type the first time it is seen. Well-known types are mapped to
+// scalars, and wrappers to the scalar they wrap.
+func (tm *typeMapper) messageType(msg *protogen.Message) string {
+	if isWrapperType(msg) {
+		return tm.scalarType(msg.Fields[0].Desc.Kind())
+	}
+	if name, ok := wellKnownTypes[msg.Desc.FullName()]; ok {
+		tm.useScalar(name)
+		return name
+	}
+	if !tm.seenObjects[msg.Desc.FullName()] {
+		tm.seenObjects[msg.Desc.FullName()] = true
+		tm.objects = append(tm.objects, msg)
+	}
+	return tm.typeName(msg.Desc)
+}
+
+// useScalar records a scalar as used so that it is declared in the schema
+func (tm *typeMapper) useScalar(name string) {
+	if !builtinScalars[name] {
+		tm.used[name] = true
+	}
+}
+
+// Scalars returns the custom scalars used so far, sorted by name
+func (tm *typeMapper) Scalars() []*Scalar {
+	var scalars []*Scalar
+	for name := range tm.used {
+		scalars = append(scalars, &Scalar{
+			Name:    name,
+			Comment: scalarDescriptions[name],
+		})
+	}
+	sort.Slice(scalars, func(i, j int) bool {
+		return scalars[i].Name < scalars[j].Name
+	})
+	return scalars
+}
+
+// Enums returns the GraphQL enums for the proto enums used so far, in the
+// order they were first referenced
+func (tm *typeMapper) Enums() []*Enum {
+	var enums []*Enum
+	for _, e := range tm.enums {
+		enums = append(enums, extractEnum(e, tm.typeName(e.Desc), tm.opts.EnumDropUnspecified))
+	}
+	return enums
+}
+
+// mapEntry returns the entry type for the map field f, registering it the
+// first time the map is seen. Entry types are named after the message and
+// field they belong to (Product.labels becomes ProductLabelsEntry) as proto
+// reuses the same LabelsEntry name in every message.
+func (tm *typeMapper) mapEntry(f *protogen.Field) *MapEntry {
+	if entry, ok := tm.seenMapEntries[f.Message.Desc.FullName()]; ok {
+		return entry
+	}
+
+	entry := &MapEntry{
+		Name:    tm.generatedTypeName(f.Message.Desc, tm.typeName(f.Parent.Desc)+string(f.Message.Desc.Name())),
+		KeyType: tm.graphQLType(f.Message.Fields[0], outputUse),
+	}
+	tm.seenMapEntries[f.Message.Desc.FullName()] = entry
+	tm.mapEntries = append(tm.mapEntries, entry)
+	return entry
+}
+
+// MapEntries returns the entry types for the map fields used so far, in the
+// order they were first referenced
+func (tm *typeMapper) MapEntries() []*MapEntry {
+	return tm.mapEntries
+}
@@ -0,0 +1,55 @@
+package generator
+
+import (
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// wellKnownTypes maps google.protobuf well-known types to the GraphQL
+// scalars that represent them. Wrapper types are handled separately as they
+// map to their underlying scalar.
+var wellKnownTypes = map[protoreflect.FullName]string{
+	"google.protobuf.Timestamp": "DateTime",
+	"google.protobuf.Duration":  "Duration",
+	"google.protobuf.Struct":    "JSON",
+	"google.protobuf.Value":     "JSON",
+	"google.protobuf.ListValue": "JSON",
+	"google.protobuf.Any":       "JSON",
+	"google.protobuf.FieldMask": "FieldMask",
+	"google.protobuf.Empty":     "Boolean",
+}
+
+// wrapperTypes are the google.protobuf wrappers for scalar values
+var wrapperTypes = map[protoreflect.FullName]bool{
+	"google.protobuf.DoubleValue": true,
+	"google.protobuf.FloatValue":  true,
+	"google.protobuf.Int64Value":  true,
+	"google.protobuf.UInt64Value": true,
+	"google.protobuf.Int32Value":  true,
+	"google.protobuf.UInt32Value": true,
+	"google.protobuf.BoolValue":   true,
+	"google.protobuf.StringValue": true,
+	"google.protobuf.BytesValue":  true,
+}
+
+// isWellKnownType reports whether msg is a well-known type that is mapped to
+// a scalar rather than generated as an object type
+func isWellKnownType(msg *protogen.Message) bool {
+	if msg == nil {
+		return false
+	}
+	_, ok := wellKnownTypes[msg.Desc.FullName()]
+	return ok || isWrapperType(msg)
+}
+
+// isWellKnownName reports whether the message name is a well-known type that
+// is mapped to a scalar
+func isWellKnownName(name protoreflect.FullName) bool {
+	_, ok := wellKnownTypes[name]
+	return ok || wrapperTypes[name]
+}
+
+// isWrapperType reports whether msg is one of the google.protobuf wrappers
+func isWrapperType(msg *protogen.Message) bool {
+	return msg != nil && wrapperTypes[msg.Desc.FullName()]
+}
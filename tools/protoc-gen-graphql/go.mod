@@ -2,4 +2,13 @@ module github.com/fraser-isbester/federated-gql/tools/protoc-gen-graphql
 
 go 1.24.0
 
-require google.golang.org/protobuf v1.36.5
+replace github.com/fraser-isbester/federated-gql/gen/go => ../../gen/go
+
+require (
+	github.com/bufbuild/protocompile v0.14.1
+	github.com/fraser-isbester/federated-gql/gen/go v0.0.0-00010101000000-000000000000
+	google.golang.org/genproto/googleapis/api v0.0.0-20250218202821-56aae31c358a
+	google.golang.org/protobuf v1.36.5
+)
+
+require golang.org/x/sync v0.8.0 // indirect
@@ -5,14 +5,10 @@ import (
 	"log"
 	"os"
 
+	"github.com/fraser-isbester/federated-gql/tools/protoc-gen-graphql/generator"
 	"google.golang.org/protobuf/compiler/protogen"
 )
 
-// Options for the generator
-type Options struct {
-	TemplatePath string // Path to custom template file (falls back to embedded template if not provided)
-}
-
 func main() {
 	log.SetPrefix("protoc-gen-graphql: ")
 	log.SetFlags(0)
@@ -20,13 +16,13 @@ func main() {
 	log.Println("Starting protoc-gen-graphql...")
 	var flags flag.FlagSet
 
-	opts := Options{}
-	flags.StringVar(&opts.TemplatePath, "template_path", "", "Path to custom template file (falls back to embedded template if not provided)")
+	opts := generator.Options{}
+	opts.RegisterFlags(&flags)
 
 	protogen.Options{
 		ParamFunc: flags.Set,
 	}.Run(func(gen *protogen.Plugin) error {
-		g, err := newGenerator(opts)
+		g, err := generator.NewGenerator(opts)
 		if err != nil {
 			log.Fatalf("failed to create generator: %v", err)
 			return err